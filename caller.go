@@ -0,0 +1,115 @@
+package logs
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// CallerFormat is the format of the caller in the logs.
+type CallerFormat int
+
+const (
+	// CallerFileName shows the name of the file, the line and the name of the function. Example: main.go:12:run()
+	// It is the default format.
+	CallerFileName CallerFormat = iota
+	// CallerShortPath shows the folder and the name of the file, the line and the name of the function.
+	// Example: cmd/main.go:12:run()
+	CallerShortPath
+	// CallerFullPath shows the full path of the file, the line and the name of the function with its package.
+	// Example: /src/app/cmd/main.go:12:github.com/user/app/cmd.run()
+	CallerFullPath
+	// CallerFunctionName shows only the name of the function with its package. Example: github.com/user/app/cmd.run()
+	CallerFunctionName
+)
+
+var (
+	// stdPackages are the packages of the standard library that write to the logs on behalf of the caller.
+	stdPackages = []string{"log.", "log/slog.", "fmt.", "io."}
+	// packageDir is the folder of the source files of this package. It is used to skip its frames.
+	packageDir = func() string {
+		_, file, _, _ := runtime.Caller(0)
+		return filepath.Dir(file)
+	}()
+	// helpers are the names of the functions marked with Helper.
+	helpers sync.Map
+)
+
+// Helper marks the calling function as a logging helper, like testing.T.Helper. The logs registered inside
+// the helper are reported with the caller of the helper instead.
+func Helper() {
+	pc, _, _, ok := runtime.Caller(1)
+	if !ok {
+		return
+	}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		helpers.Store(fn.Name(), struct{}{})
+	}
+}
+
+// AddCallerSkip returns a copy of the service that skips n more frames when it looks for the caller of the logs.
+// It is used when the service is wrapped by functions that should not be reported as the caller.
+func (s Service) AddCallerSkip(n int) Service {
+	s.callerSkip += n
+	return s
+}
+
+// caller returns the first frame that is not part of this package, of the standard library packages that write
+// on behalf of the caller, or of a function marked with Helper. Then the frames of AddCallerSkip are skipped.
+func (s Service) caller() (runtime.Frame, bool) {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	skip := s.callerSkip
+	for {
+		frame, more := frames.Next()
+		if !internalFrame(frame) {
+			if skip == 0 {
+				return frame, true
+			}
+			skip--
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// callerDecorator sets in the entry the file, line and function of the frame, in the CallerFormat of the service.
+func (s Service) callerDecorator(entry *Entry, frame runtime.Frame) {
+	entry.Line = frame.Line
+	switch s.CallerFormat {
+	case CallerShortPath:
+		entry.File = filepath.Join(filepath.Base(filepath.Dir(frame.File)), filepath.Base(frame.File))
+		entry.Function = shortFunction(frame.Function)
+	case CallerFullPath:
+		entry.File = frame.File
+		entry.Function = frame.Function
+	case CallerFunctionName:
+		entry.Function = frame.Function
+	default:
+		entry.File = filepath.Base(frame.File)
+		entry.Function = shortFunction(frame.Function)
+	}
+}
+
+// shortFunction returns the name of the function without its package and receiver.
+func shortFunction(function string) string {
+	fns := strings.Split(function, ".")
+	return fns[len(fns)-1]
+}
+
+// internalFrame returns true when the frame belongs to this package, without its tests, to the standard
+// library packages that write on behalf of the caller, or to a function marked with Helper.
+func internalFrame(frame runtime.Frame) bool {
+	if filepath.Dir(frame.File) == packageDir && !strings.HasSuffix(frame.File, "_test.go") {
+		return true
+	}
+	for _, pkg := range stdPackages {
+		if strings.HasPrefix(frame.Function, pkg) {
+			return true
+		}
+	}
+	_, helper := helpers.Load(frame.Function)
+	return helper
+}
@@ -0,0 +1,478 @@
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaKind is the kind of value of a key of the configuration file.
+type schemaKind int
+
+const (
+	schemaString schemaKind = iota
+	schemaBool
+	schemaLevel
+	schemaDuration
+	schemaRate
+	schemaPattern
+	schemaEnum
+	schemaObject
+	schemaList
+)
+
+// schema describes the values accepted by a key of the configuration file.
+type schema struct {
+	kind schemaKind
+	// values are the values accepted by a schemaEnum.
+	values []string
+	// fields are the keys accepted by a schemaObject.
+	fields map[string]schema
+	// required are the keys required by a schemaObject.
+	required []string
+	// requiredWhen are the keys required by a schemaObject when another key has a value.
+	requiredWhen []schemaCondition
+	// items is the schema of the items of a schemaList. The items of the sinks are validated with sinkSchemas.
+	items *schema
+}
+
+// schemaCondition is a key required when another key of the object has a value.
+type schemaCondition struct {
+	key      string
+	value    string
+	required string
+}
+
+var (
+	// formatSchema is the schema of the keys of a format.
+	formatSchema = schema{kind: schemaEnum, values: []string{string(FormatText), string(FormatJSON), string(FormatPretty)}}
+	// sinkSchemas are the schemas of the sinks by type. The keys of all the sinks are type, level, format,
+	// show_date, show_time, dedup, rate_limit and overflow.
+	sinkSchemas = map[string]schema{
+		"console": sinkSchema(nil, map[string]schema{
+			"output": {kind: schemaEnum, values: []string{"stdout", "stderr"}},
+		}),
+		"file": sinkSchema([]string{"path"}, map[string]schema{
+			"path": {kind: schemaString},
+		}),
+		"webhook": sinkSchema([]string{"url"}, map[string]schema{
+			"url": {kind: schemaString},
+		}),
+		"syslog": sinkSchema([]string{"address"}, map[string]schema{
+			"address":  {kind: schemaString},
+			"network":  {kind: schemaEnum, values: []string{"udp", "tcp"}},
+			"facility": {kind: schemaEnum, values: facilityNames()},
+			"tag":      {kind: schemaString},
+			"host":     {kind: schemaString},
+		}),
+	}
+	// configSchema is the schema of the configuration file.
+	configSchema = schema{kind: schemaObject, fields: map[string]schema{
+		"app":           {kind: schemaString},
+		"level":         {kind: schemaLevel},
+		"format":        formatSchema,
+		"show_date":     {kind: schemaBool},
+		"show_time":     {kind: schemaBool},
+		"caller_format": {kind: schemaEnum, values: []string{"file", "short", "full", "function"}},
+		"stack_level":   {kind: schemaLevel},
+		"redaction": {kind: schemaObject, fields: map[string]schema{
+			"detectors": {kind: schemaList, items: &schema{kind: schemaEnum, values: detectorNames()}},
+			"patterns":  {kind: schemaList, items: &schema{kind: schemaPattern}},
+			"fields":    {kind: schemaList, items: &schema{kind: schemaString}},
+			"mask":      {kind: schemaEnum, values: []string{string(MaskFull), string(MaskPartial), string(MaskHash)}},
+			"hash_key":  {kind: schemaString},
+		}, requiredWhen: []schemaCondition{{key: "mask", value: string(MaskHash), required: "hash_key"}}},
+		"sinks": {kind: schemaList, items: &schema{kind: schemaObject}},
+	}}
+)
+
+// configFile is the content of the configuration file.
+type configFile struct {
+	App          string           `yaml:"app"`
+	Level        string           `yaml:"level"`
+	Format       string           `yaml:"format"`
+	ShowDate     bool             `yaml:"show_date"`
+	ShowTime     bool             `yaml:"show_time"`
+	CallerFormat string           `yaml:"caller_format"`
+	StackLevel   string           `yaml:"stack_level"`
+	Redaction    *configRedaction `yaml:"redaction"`
+	Sinks        []configSink     `yaml:"sinks"`
+}
+
+// configRedaction is the configuration of the redaction in the configuration file.
+type configRedaction struct {
+	Detectors []string `yaml:"detectors"`
+	Patterns  []string `yaml:"patterns"`
+	Fields    []string `yaml:"fields"`
+	Mask      string   `yaml:"mask"`
+	HashKey   string   `yaml:"hash_key"`
+}
+
+// configSink is the configuration of a sink in the configuration file.
+type configSink struct {
+	Type      string `yaml:"type"`
+	Level     string `yaml:"level"`
+	Format    string `yaml:"format"`
+	ShowDate  bool   `yaml:"show_date"`
+	ShowTime  bool   `yaml:"show_time"`
+	Output    string `yaml:"output"`
+	Path      string `yaml:"path"`
+	URL       string `yaml:"url"`
+	Address   string `yaml:"address"`
+	Network   string `yaml:"network"`
+	Facility  string `yaml:"facility"`
+	Tag       string `yaml:"tag"`
+	Host      string `yaml:"host"`
+	Dedup     string `yaml:"dedup"`
+	RateLimit string `yaml:"rate_limit"`
+	Overflow  string `yaml:"overflow"`
+}
+
+// LoadConfig returns a new instance of a Service configured with the YAML or JSON file of the path provided.
+// The file is validated before the service is created, and all the errors are returned with their line.
+// If the file has no sinks, the logs are printed in the console. Example:
+//
+//	app: api
+//	level: info
+//	redaction:
+//	  mask: partial
+//	  fields: [password, authorization]
+//	sinks:
+//	  - type: console
+//	    format: json
+//	  - type: file
+//	    path: /var/log/api/api.log
+//	  - type: webhook
+//	    url: https://discordapp.com/api/webhooks/1234567890/abcdefghijklmnopqrstuvwxyz
+//	    level: error
+//	    dedup: 1m
+//	    rate_limit: 30/1m
+//	  - type: syslog
+//	    address: localhost:514
+//	    facility: local0
+//
+// The sinks accept a level, a format, show_date and show_time, dedup, the window of the DedupSink, and rate_limit and
+// overflow, the rate and the overflow of the RateLimitSink. The console accepts an output, stdout or stderr.
+// The syslog accepts a network, a facility, a tag and a host.
+// The redaction accepts the detectors, the patterns, the fields, the mask and the hash_key of the RedactionConfig. The
+// hash_key is required by the hash mask.
+func LoadConfig(path string) (Service, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Service{}, fmt.Errorf("logs: %w", err)
+	}
+	return parseConfig(path, data)
+}
+
+// parseConfig returns a new instance of a Service configured with the content of a configuration file.
+// The name of the file is used in the errors.
+func parseConfig(name string, data []byte) (Service, error) {
+	config, err := readConfig(name, data)
+	if err != nil {
+		return Service{}, err
+	}
+	return config.service(config.sinks()), nil
+}
+
+// readConfig returns the configuration of the content of a configuration file, after it is validated.
+// The name of the file is used in the errors.
+func readConfig(name string, data []byte) (configFile, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		// The tabs are not valid indentation in YAML, and they can only be whitespace in JSON.
+		data = bytes.ReplaceAll(data, []byte("\t"), []byte(" "))
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return configFile{}, fmt.Errorf("logs: %s: %w", name, err)
+	}
+	if len(document.Content) == 0 {
+		return configFile{}, fmt.Errorf("logs: %s: the file is empty", name)
+	}
+	root := document.Content[0]
+	var errs []error
+	validate(root, configSchema, "", func(node *yaml.Node, path string, message string) {
+		errs = append(errs, fmt.Errorf("logs: %s:%d: %s: %s", name, node.Line, path, message))
+	})
+	if len(errs) > 0 {
+		return configFile{}, errors.Join(errs...)
+	}
+	var config configFile
+	if err := root.Decode(&config); err != nil {
+		return configFile{}, fmt.Errorf("logs: %s: %w", name, err)
+	}
+	return config, nil
+}
+
+// service returns the service of the configuration with the sinks provided. The configuration must be valid.
+func (c configFile) service(sinks []Sink) Service {
+	stackLevel, _ := ParseLevel(c.StackLevel)
+	return NewService(Service{
+		NameApp:      c.App,
+		MinLevel:     c.level(),
+		Format:       Format(c.Format),
+		ShowDate:     c.ShowDate,
+		ShowTime:     c.ShowTime,
+		CallerFormat: callerFormats[c.CallerFormat],
+		StackLevel:   stackLevel,
+		Redaction:    c.Redaction.config(),
+		Sinks:        sinks,
+	})
+}
+
+// config returns the RedactionConfig of the configuration. It returns nil when the redaction is not provided.
+func (c *configRedaction) config() *RedactionConfig {
+	if c == nil {
+		return nil
+	}
+	config := &RedactionConfig{Fields: c.Fields, Mask: Mask(c.Mask)}
+	for _, name := range c.Detectors {
+		config.Detectors = append(config.Detectors, Detector(name))
+	}
+	if c.Detectors != nil && config.Detectors == nil {
+		config.Detectors = []Detector{}
+	}
+	for _, pattern := range c.Patterns {
+		config.Patterns = append(config.Patterns, regexp.MustCompile(pattern))
+	}
+	if c.HashKey != "" {
+		config.HashKey = []byte(c.HashKey)
+	}
+	return config
+}
+
+// level returns the minimum level of the configuration. If it is not provided, it will be LevelTrace.
+func (c configFile) level() Level {
+	level, err := ParseLevel(c.Level)
+	if err != nil {
+		return LevelTrace
+	}
+	return level
+}
+
+// sinks returns the sinks of the configuration. If there are no sinks, the logs are printed in the console.
+func (c configFile) sinks() []Sink {
+	var sinks []Sink
+	for _, sink := range c.Sinks {
+		sinks = append(sinks, sink.sink())
+	}
+	if len(sinks) == 0 {
+		sinks = []Sink{NewConsoleSink(ConsoleConfig{})}
+	}
+	return sinks
+}
+
+// sink returns the sink of the configuration, wrapped in a RateLimitSink when it has a rate limit, in a DedupSink when
+// it has a dedup window and in a LevelSink when it has a level.
+func (c configSink) sink() Sink {
+	formatter := Formatter{Format: Format(c.Format), ShowDate: c.ShowDate, ShowTime: c.ShowTime}
+	var sink Sink
+	switch c.Type {
+	case "file":
+		sink = NewFileSink(FileConfig{Path: c.Path, Formatter: formatter})
+	case "webhook":
+		sink = NewWebhookSink(WebhookConfig{URL: c.URL, Formatter: formatter})
+	case "syslog":
+		sink = NewSyslogSink(SyslogConfig{Address: c.Address, Network: c.Network, Facility: c.Facility, Tag: c.Tag, Host: c.Host})
+	default:
+		config := ConsoleConfig{Formatter: formatter}
+		if c.Output == "stderr" {
+			config.Writer = os.Stderr
+		}
+		sink = NewConsoleSink(config)
+	}
+	if rate, per, err := parseRate(c.RateLimit); err == nil {
+		sink = NewRateLimitSink(RateLimitConfig{Sink: sink, Rate: rate, Per: per, Overflow: RateLimitOverflow(c.Overflow)})
+	}
+	if window, err := time.ParseDuration(c.Dedup); err == nil {
+		sink = NewDedupSink(DedupConfig{Sink: sink, Window: window, Formatter: formatter})
+	}
+	if c.Level == "" {
+		return sink
+	}
+	level, _ := ParseLevel(c.Level)
+	return NewLevelSink(LevelSinkConfig{Sink: sink, MinLevel: level})
+}
+
+// validate checks the node with the schema and reports every error with the node and the path of the key.
+func validate(node *yaml.Node, s schema, path string, report func(node *yaml.Node, path string, message string)) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	switch s.kind {
+	case schemaObject:
+		if node.Kind != yaml.MappingNode {
+			report(node, pathName(path), "it must be an object")
+			return
+		}
+		if s.fields == nil {
+			validateSink(node, path, report)
+			return
+		}
+		seen := map[string]bool{}
+		values := map[string]*yaml.Node{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			values[key.Value] = value
+			field, ok := s.fields[key.Value]
+			switch {
+			case !ok:
+				report(key, joinPath(path, key.Value), fmt.Sprintf("unknown key, it must be one of %s", strings.Join(schemaKeys(s), ", ")))
+			case seen[key.Value]:
+				report(key, joinPath(path, key.Value), "duplicated key")
+			default:
+				validate(value, field, joinPath(path, key.Value), report)
+			}
+			seen[key.Value] = true
+		}
+		for _, key := range s.required {
+			if !seen[key] {
+				report(node, pathName(path), fmt.Sprintf("the key %s is required", key))
+			}
+		}
+		for _, condition := range s.requiredWhen {
+			if value, ok := values[condition.key]; ok && value.Value == condition.value && !seen[condition.required] {
+				report(value, joinPath(path, condition.key), fmt.Sprintf("the key %s is required when it is %s", condition.required, condition.value))
+			}
+		}
+	case schemaList:
+		if node.Kind != yaml.SequenceNode {
+			report(node, pathName(path), "it must be a list")
+			return
+		}
+		for i, item := range node.Content {
+			validate(item, *s.items, fmt.Sprintf("%s[%d]", path, i), report)
+		}
+	default:
+		if node.Kind != yaml.ScalarNode {
+			report(node, pathName(path), "it must be a single value")
+			return
+		}
+		validateScalar(node, s, path, report)
+	}
+}
+
+// validateSink checks the node of a sink with the schema of its type.
+func validateSink(node *yaml.Node, path string, report func(node *yaml.Node, path string, message string)) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "type" {
+			continue
+		}
+		value := node.Content[i+1]
+		s, ok := sinkSchemas[value.Value]
+		if !ok {
+			types := make([]string, 0, len(sinkSchemas))
+			for name := range sinkSchemas {
+				types = append(types, name)
+			}
+			sort.Strings(types)
+			report(value, joinPath(path, "type"), fmt.Sprintf("unknown sink %q, it must be one of %s", value.Value, strings.Join(types, ", ")))
+			return
+		}
+		validate(node, s, path, report)
+		return
+	}
+	report(node, pathName(path), "the key type is required")
+}
+
+// validateScalar checks the value of a scalar node with the schema.
+func validateScalar(node *yaml.Node, s schema, path string, report func(node *yaml.Node, path string, message string)) {
+	switch s.kind {
+	case schemaBool:
+		if node.Tag != "!!bool" {
+			report(node, path, fmt.Sprintf("%q must be true or false", node.Value))
+		}
+	case schemaLevel:
+		if _, err := ParseLevel(node.Value); err != nil {
+			report(node, path, fmt.Sprintf("unknown level %q", node.Value))
+		}
+	case schemaDuration:
+		if duration, err := time.ParseDuration(node.Value); err != nil || duration <= 0 {
+			report(node, path, fmt.Sprintf("%q must be a positive duration, like 1m", node.Value))
+		}
+	case schemaRate:
+		if _, _, err := parseRate(node.Value); err != nil {
+			report(node, path, fmt.Sprintf("%q must be a rate like 30/1m", node.Value))
+		}
+	case schemaPattern:
+		if _, err := regexp.Compile(node.Value); err != nil {
+			report(node, path, fmt.Sprintf("%q must be a regular expression", node.Value))
+		}
+	case schemaEnum:
+		for _, value := range s.values {
+			if node.Value == value {
+				return
+			}
+		}
+		report(node, path, fmt.Sprintf("%q must be one of %s", node.Value, strings.Join(s.values, ", ")))
+	default:
+		if node.Tag == "!!null" || node.Value == "" {
+			report(node, path, "it must not be empty")
+		}
+	}
+}
+
+// sinkSchema returns the schema of a sink with the keys of all the sinks and the keys provided.
+func sinkSchema(required []string, fields map[string]schema) schema {
+	fields["type"] = schema{kind: schemaString}
+	fields["level"] = schema{kind: schemaLevel}
+	fields["format"] = formatSchema
+	fields["show_date"] = schema{kind: schemaBool}
+	fields["show_time"] = schema{kind: schemaBool}
+	fields["dedup"] = schema{kind: schemaDuration}
+	fields["rate_limit"] = schema{kind: schemaRate}
+	fields["overflow"] = schema{kind: schemaEnum, values: []string{string(RateLimitDrop), string(RateLimitDigest)}}
+	return schema{kind: schemaObject, fields: fields, required: required}
+}
+
+// schemaKeys returns the sorted keys of an object schema.
+func schemaKeys(s schema) []string {
+	keys := make([]string, 0, len(s.fields))
+	for key := range s.fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// facilityNames returns the sorted names of the syslog facilities.
+func facilityNames() []string {
+	names := make([]string, 0, len(syslogFacilities))
+	for name := range syslogFacilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// detectorNames returns the sorted names of the built-in detectors.
+func detectorNames() []string {
+	names := make([]string, 0, len(detectors))
+	for name := range detectors {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// joinPath returns the path of the key inside the path of its object.
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// pathName returns the path, or "config" for the root of the file.
+func pathName(path string) string {
+	if path == "" {
+		return "config"
+	}
+	return path
+}
@@ -0,0 +1,153 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs.yml")
+	logPath := filepath.Join(dir, "app", "app.log")
+	require.NoError(t, os.WriteFile(path, []byte(`
+app: api
+level: debug
+format: json
+caller_format: short
+redaction:
+  mask: partial
+  fields: [password]
+sinks:
+  - type: console
+    output: stderr
+    level: error
+  - type: file
+    path: `+logPath+`
+    format: text
+  - type: webhook
+    url: http://localhost:8080
+    level: error
+    dedup: 1m
+    rate_limit: 30/1m
+    overflow: digest
+  - type: syslog
+    address: localhost:514
+    facility: local0
+`), 0644))
+
+	service, err := LoadConfig(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "api", service.NameApp)
+	assert.Equal(t, LevelDebug, service.CurrentLevel())
+	assert.Equal(t, FormatJSON, service.Format)
+	assert.Equal(t, CallerShortPath, service.CallerFormat)
+	assert.Equal(t, &RedactionConfig{Fields: []string{"password"}, Mask: MaskPartial}, service.Redaction)
+	require.Len(t, service.Sinks, 4)
+	assert.IsType(t, &LevelSink{}, service.Sinks[0])
+	assert.Equal(t, &FileSink{config: FileConfig{Path: logPath, Formatter: Formatter{Format: FormatText}}}, service.Sinks[1])
+	dedup := service.Sinks[2].(*LevelSink).config.Sink.(*DedupSink)
+	rateLimit := dedup.config.Sink.(*RateLimitSink)
+	assert.Equal(t, 30, rateLimit.config.Rate)
+	assert.Equal(t, RateLimitDigest, rateLimit.config.Overflow)
+	assert.Equal(t, 16, service.Sinks[3].(*SyslogSink).facility)
+
+	service.Trace("dropped")
+	service.With(Any("user", "fsandov")).Info("message")
+	assert.NoError(t, service.Sinks[1].(*FileSink).Close())
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "[api]-[INFO] message user=fsandov\n", string(content))
+}
+
+func Test_parseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		sinks   int
+		wantErr string
+	}{
+		{
+			name:  "parseConfig with JSON",
+			data:  "{\n\t\"app\": \"api\",\n\t\"sinks\": [{\"type\": \"console\", \"format\": \"json\"}]\n}",
+			sinks: 1,
+		},
+		{
+			name:  "parseConfig without sinks",
+			data:  "app: api",
+			sinks: 1,
+		},
+		{
+			name: "parseConfig with invalid values",
+			data: `app: api
+level: verbose
+show_date: sometimes
+colors: true
+sinks:
+  - type: file
+    format: xml
+    dedup: often
+    rate_limit: fast
+  - type: kafka
+  - level: info
+`,
+			wantErr: `logs: logs.yml:2: level: unknown level "verbose"
+logs: logs.yml:3: show_date: "sometimes" must be true or false
+logs: logs.yml:4: colors: unknown key, it must be one of app, caller_format, format, level, redaction, show_date, show_time, sinks, stack_level
+logs: logs.yml:7: sinks[0].format: "xml" must be one of text, json, pretty
+logs: logs.yml:8: sinks[0].dedup: "often" must be a positive duration, like 1m
+logs: logs.yml:9: sinks[0].rate_limit: "fast" must be a rate like 30/1m
+logs: logs.yml:6: sinks[0]: the key path is required
+logs: logs.yml:10: sinks[1].type: unknown sink "kafka", it must be one of console, file, syslog, webhook
+logs: logs.yml:11: sinks[2]: the key type is required`,
+		},
+		{
+			name: "parseConfig with an invalid redaction",
+			data: `app: api
+redaction:
+  detectors: [email, phone]
+  patterns: ["user=("]
+  mask: scramble
+`,
+			wantErr: `logs: logs.yml:3: redaction.detectors[1]: "phone" must be one of aws_key, bearer_token, credit_card, email, ip, jwt
+logs: logs.yml:4: redaction.patterns[0]: "user=(" must be a regular expression
+logs: logs.yml:5: redaction.mask: "scramble" must be one of full, partial, hash`,
+		},
+		{
+			name: "parseConfig with the hash mask without a key",
+			data: `app: api
+redaction:
+  fields: [password]
+  mask: hash
+`,
+			wantErr: `logs: logs.yml:4: redaction.mask: the key hash_key is required when it is hash`,
+		},
+		{
+			name:    "parseConfig with a list as root",
+			data:    "- app",
+			wantErr: "logs: logs.yml:1: config: it must be an object",
+		},
+		{
+			name:    "parseConfig with invalid YAML",
+			data:    "app: [api",
+			wantErr: "logs: logs.yml: yaml: line 1: did not find expected ',' or ']'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, err := parseConfig("logs.yml", []byte(tt.data))
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "api", service.NameApp)
+			assert.Len(t, service.Sinks, tt.sinks)
+		})
+	}
+}
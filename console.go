@@ -0,0 +1,48 @@
+package logs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ConsoleConfig is the struct that contains the configuration of the ConsoleSink. All the fields are optional.
+type ConsoleConfig struct {
+	// Writer is where the logs are printed. If it is not provided, it will be os.Stdout.
+	Writer io.Writer
+	// Formatter is the format of the logs. If it is not provided, the logs are printed in the format of the service.
+	// With FormatPretty, the logs are colorized when the Writer is a terminal and NO_COLOR is not set.
+	Formatter Formatter
+}
+
+// ConsoleSink is a sink that prints the logs in the console, one per line.
+type ConsoleSink struct {
+	config ConsoleConfig
+	// color is true when the logs of FormatPretty are colorized.
+	color bool
+	mu    sync.Mutex
+}
+
+// NewConsoleSink returns a new ConsoleSink with the configuration provided.
+func NewConsoleSink(config ConsoleConfig) *ConsoleSink {
+	if config.Writer == nil {
+		config.Writer = os.Stdout
+	}
+	return &ConsoleSink{config: config, color: colorEnabled(config.Writer)}
+}
+
+// Write prints the entry.
+func (c *ConsoleSink) Write(entry Entry) error {
+	content := c.config.Formatter.content(entry)
+	if c.config.Formatter.Format == FormatPretty {
+		content = prettyContent(entry, c.config.Formatter.ShowDate, c.color)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	start := time.Now()
+	n, err := fmt.Fprintln(c.config.Writer, content)
+	metrics.delivered(sinkConsole, n, start, err)
+	return err
+}
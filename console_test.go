@@ -0,0 +1,51 @@
+package logs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsoleSink_Write(t *testing.T) {
+	entry := Entry{
+		Time:     time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		Level:    LevelError,
+		NameApp:  "LOGS",
+		Message:  "message",
+		Content:  "[LOGS]-[ERROR] main.go:12:run(): message",
+		File:     "main.go",
+		Line:     12,
+		Function: "run",
+	}
+	tests := []struct {
+		name      string
+		formatter Formatter
+		want      string
+	}{
+		{
+			name: "Write with the format of the service",
+			want: "[LOGS]-[ERROR] main.go:12:run(): message\n",
+		},
+		{
+			name:      "Write with the date",
+			formatter: Formatter{Format: FormatText, ShowDate: true},
+			want:      "[2024-01-02][LOGS]-[ERROR] main.go:12:run(): message\n",
+		},
+		{
+			name:      "Write with FormatJSON",
+			formatter: Formatter{Format: FormatJSON},
+			want:      `{"time":"2024-01-02T15:04:05Z","level":"ERROR","app":"LOGS","message":"message","file":"main.go","line":12,"function":"run"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			sink := NewConsoleSink(ConsoleConfig{Writer: &buffer, Formatter: tt.formatter})
+
+			assert.NoError(t, sink.Write(entry))
+			assert.Equal(t, tt.want, buffer.String())
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package logs
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// TraceIDField is the field where the trace ID of the context is attached to the logs.
+	TraceIDField = "trace_id"
+	// SpanIDField is the field where the span ID of the context is attached to the logs.
+	SpanIDField = "span_id"
+	// RequestIDField is the field where the request ID of the context is attached to the logs.
+	RequestIDField = "request_id"
+)
+
+// contextKey is the type of the keys of the values saved in the context by this package.
+type contextKey int
+
+const (
+	traceContextKey contextKey = iota
+	requestIDContextKey
+	serviceContextKey
+)
+
+// traceContext is the trace and span of a context, in lowercase hex.
+type traceContext struct {
+	traceID string
+	spanID  string
+}
+
+// ContextWithTraceparent returns a copy of the context with the trace and span of a W3C traceparent header.
+// Example: 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+// If the header is not valid, the context is returned without changes.
+func ContextWithTraceparent(ctx context.Context, traceparent string) context.Context {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return ctx
+	}
+	if parts[0] == "00" && len(parts) != 4 {
+		return ctx
+	}
+	return ContextWithTrace(ctx, parts[1], parts[2])
+}
+
+// ContextWithTrace returns a copy of the context with the trace ID and span ID provided, in hex.
+// If the IDs are not valid, the context is returned without changes.
+func ContextWithTrace(ctx context.Context, traceID string, spanID string) context.Context {
+	traceID = strings.ToLower(traceID)
+	spanID = strings.ToLower(spanID)
+	if !validID(traceID, 16) || !validID(spanID, 8) {
+		return ctx
+	}
+	return context.WithValue(ctx, traceContextKey, traceContext{traceID: traceID, spanID: spanID})
+}
+
+// TraceFromContext returns the trace ID and span ID of the context. They are empty if the context has no trace.
+func TraceFromContext(ctx context.Context) (traceID string, spanID string) {
+	trace, _ := ctx.Value(traceContextKey).(traceContext)
+	return trace.traceID, trace.spanID
+}
+
+// ContextWithRequestID returns a copy of the context with the request ID provided.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+// RequestIDFromContext returns the request ID of the context. It is empty if the context has no request ID.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
+
+// NewContext returns a copy of the context with the service provided. It is used to pass a child service, like the one
+// of a request, to the functions that receive the context.
+func NewContext(ctx context.Context, service Service) context.Context {
+	return context.WithValue(ctx, serviceContextKey, service)
+}
+
+// FromContext returns the service of the context. If the context has no service, it returns the DefaultService
+// with the trace ID, span ID and request ID of the context.
+func FromContext(ctx context.Context) Service {
+	if service, ok := ctx.Value(serviceContextKey).(Service); ok {
+		return service
+	}
+	return DefaultService.WithContext(ctx)
+}
+
+// WithContext returns a copy of the service that attaches the trace ID, span ID and request ID of the context
+// to all of its logs, as the fields TraceIDField, SpanIDField and RequestIDField.
+func (s Service) WithContext(ctx context.Context) Service {
+	var fields []Field
+	if traceID, spanID := TraceFromContext(ctx); traceID != "" {
+		fields = append(fields, Any(TraceIDField, traceID), Any(SpanIDField, spanID))
+	}
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		fields = append(fields, Any(RequestIDField, requestID))
+	}
+	if len(fields) == 0 {
+		return s
+	}
+	return s.With(fields...)
+}
+
+// validID returns true when the ID is a hex of the size provided in bytes and it is not all zeros.
+func validID(id string, size int) bool {
+	b, err := hex.DecodeString(id)
+	if err != nil || len(b) != size {
+		return false
+	}
+	return strings.Trim(id, "0") != ""
+}
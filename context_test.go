@@ -0,0 +1,107 @@
+package logs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextWithTraceparent(t *testing.T) {
+	type want struct {
+		traceID string
+		spanID  string
+	}
+	tests := []struct {
+		name        string
+		traceparent string
+		want        want
+	}{
+		{
+			name:        "ContextWithTraceparent with a valid header",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			want:        want{traceID: "4bf92f3577b34da6a3ce929d0e0e4736", spanID: "00f067aa0ba902b7"},
+		},
+		{
+			name:        "ContextWithTraceparent with uppercase IDs",
+			traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00F067AA0BA902B7-00",
+			want:        want{traceID: "4bf92f3577b34da6a3ce929d0e0e4736", spanID: "00f067aa0ba902b7"},
+		},
+		{
+			name:        "ContextWithTraceparent with an invalid trace ID",
+			traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		},
+		{
+			name:        "ContextWithTraceparent with an invalid version",
+			traceparent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:        "ContextWithTraceparent with an empty header",
+			traceparent: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ContextWithTraceparent(context.Background(), tt.traceparent)
+			traceID, spanID := TraceFromContext(ctx)
+
+			assert.Equal(t, tt.want.traceID, traceID)
+			assert.Equal(t, tt.want.spanID, spanID)
+		})
+	}
+}
+
+func TestService_WithContext(t *testing.T) {
+	type want struct {
+		Message string
+	}
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want want
+	}{
+		{
+			name: "WithContext when the context has a trace and a request ID",
+			ctx: ContextWithRequestID(
+				ContextWithTraceparent(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"),
+				"req-1"),
+			want: want{
+				Message: "[LOGS]-[INFO] message trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 request_id=req-1",
+			},
+		},
+		{
+			name: "WithContext when the context has only a request ID",
+			ctx:  ContextWithRequestID(context.Background(), "req-1"),
+			want: want{
+				Message: "[LOGS]-[INFO] message request_id=req-1",
+			},
+		},
+		{
+			name: "WithContext when the context is empty",
+			ctx:  context.Background(),
+			want: want{
+				Message: "[LOGS]-[INFO] message ",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logService := NewService(Service{})
+			entry := logService.WithContext(tt.ctx).logBuilder(LevelInfo, "message")
+
+			assert.Equal(t, tt.want.Message, entry.Content)
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	logService := NewService(Service{NameApp: "Test"}).With(Any("user", "fsandov"))
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+
+	service := FromContext(NewContext(ctx, logService))
+	assert.Equal(t, "Test", service.NameApp)
+	assert.Equal(t, []Field{Any("user", "fsandov")}, service.fields)
+	service = FromContext(ctx)
+	assert.Equal(t, DefaultService.NameApp, service.NameApp)
+	assert.Equal(t, []Field{Any(RequestIDField, "req-1")}, service.fields)
+}
@@ -0,0 +1,199 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DedupConfig is the struct that contains the configuration of the DedupSink. The Sink is required.
+type DedupConfig struct {
+	// Sink is the sink that receives the entries.
+	Sink Sink
+	// Window is the time in which the identical entries are collapsed. If it is not provided, it will be 1 minute.
+	Window time.Duration
+	// Formatter is the format of the content of the summaries. It should be the format of the entries written to the
+	// sink. If it is not provided, the summaries are in JSON when the entries are in JSON, and in text without the date
+	// and the time otherwise.
+	Formatter Formatter
+}
+
+// DedupSink is a sink that collapses the identical entries written to another sink. The entries are identical when
+// they have the same level, caller and message. The first entry is written, and the ones repeated in the Window are
+// counted and written as a single entry with the message "last message repeated N times" when the Window ends.
+// It is used to deduplicate a sink and not the others. Example: the webhook is deduplicated and the file keeps all the logs.
+// Close should be called when the sink is not used anymore.
+type DedupSink struct {
+	config  DedupConfig
+	mu      sync.Mutex
+	records map[dedupKey]*dedupRecord
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// dedupKey identifies the identical entries.
+type dedupKey struct {
+	level    Level
+	file     string
+	line     int
+	function string
+	message  string
+}
+
+// dedupRecord keeps the entries repeated in the window of an entry.
+type dedupRecord struct {
+	// until is the end of the window.
+	until time.Time
+	// last is the last repeated entry.
+	last Entry
+	// repeated is the number of entries collapsed since the entry or the last summary was written.
+	repeated int
+}
+
+// NewDedupSink returns a new DedupSink with the configuration provided. It starts the checks of the windows that
+// ended every Window.
+func NewDedupSink(config DedupConfig) *DedupSink {
+	if config.Window <= 0 {
+		config.Window = time.Minute
+	}
+	sink := &DedupSink{
+		config:  config,
+		records: map[dedupKey]*dedupRecord{},
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go sink.expireLoop()
+	return sink
+}
+
+// Write writes the entry to the sink, unless it is identical to an entry written in the Window.
+func (d *DedupSink) Write(entry Entry) error {
+	key := dedupKey{level: entry.Level, file: entry.File, line: entry.Line, function: entry.Function, message: entry.Message}
+	d.mu.Lock()
+	summaries := d.expire(entry.Time)
+	record, ok := d.records[key]
+	if ok {
+		record.last = entry
+		record.repeated++
+		metrics.dropped.add(1, entry.NameApp, dropDeduplicated)
+	} else {
+		d.records[key] = &dedupRecord{until: entry.Time.Add(d.config.Window)}
+	}
+	d.mu.Unlock()
+	err := d.write(summaries)
+	if ok {
+		return err
+	}
+	return errors.Join(err, d.config.Sink.Write(entry))
+}
+
+// Flush writes the summaries of the repeated entries, and flushes the sink when it implements Flusher.
+// The windows do not end, so the next identical entries are still collapsed.
+func (d *DedupSink) Flush() error {
+	d.mu.Lock()
+	var summaries []Entry
+	for _, record := range d.records {
+		if record.repeated > 0 {
+			summaries = append(summaries, d.summary(record))
+			record.repeated = 0
+		}
+	}
+	d.mu.Unlock()
+	err := d.write(summaries)
+	if flusher, ok := d.config.Sink.(Flusher); ok {
+		err = errors.Join(err, flusher.Flush())
+	}
+	return err
+}
+
+// Close stops the checks of the windows, writes the summaries of the repeated entries and closes the sink when it
+// implements io.Closer.
+func (d *DedupSink) Close() error {
+	select {
+	case <-d.stop:
+	default:
+		close(d.stop)
+		<-d.done
+	}
+	err := d.Flush()
+	if closer, ok := d.config.Sink.(io.Closer); ok {
+		err = errors.Join(err, closer.Close())
+	}
+	return err
+}
+
+// expireLoop writes the summaries of the windows that ended every Window until the sink is closed.
+func (d *DedupSink) expireLoop() {
+	defer close(d.done)
+	ticker := time.NewTicker(d.config.Window)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			d.mu.Lock()
+			summaries := d.expire(now)
+			d.mu.Unlock()
+			if err := d.write(summaries); err != nil {
+				fmt.Println(err)
+			}
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// expire removes the records of the windows that ended and returns their summaries. It must be called with the lock.
+func (d *DedupSink) expire(now time.Time) []Entry {
+	var summaries []Entry
+	for key, record := range d.records {
+		if now.Before(record.until) {
+			continue
+		}
+		if record.repeated > 0 {
+			summaries = append(summaries, d.summary(record))
+		}
+		delete(d.records, key)
+	}
+	return summaries
+}
+
+// write writes the entries to the sink.
+func (d *DedupSink) write(entries []Entry) error {
+	var errs []error
+	for _, entry := range entries {
+		if err := d.config.Sink.Write(entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// summary returns the entry that reports the repeated entries of the record. It is the last repeated entry with
+// the message "last message repeated N times", and its content is built again in the format of the Formatter.
+func (d *DedupSink) summary(record *dedupRecord) Entry {
+	entry := record.last
+	entry.Message = fmt.Sprintf("last message repeated %d times", record.repeated)
+	formatter := d.config.Formatter
+	if formatter == (Formatter{}) {
+		formatter.Format = FormatText
+		if strings.HasPrefix(entry.Content, "{") {
+			formatter.Format = FormatJSON
+		}
+	}
+	entry.Content = formatter.content(entry)
+	return entry
+}
+
+// urlSink is the sink of the URL of a service, used to deduplicate and to rate limit the logs sent to the URL.
+type urlSink struct {
+	service Service
+}
+
+// Write sends the entry to the URL of the service.
+func (u urlSink) Write(entry Entry) error {
+	u.service.postLog(discordContent(entry))
+	return nil
+}
@@ -0,0 +1,142 @@
+package logs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupSink_Write(t *testing.T) {
+	sink := &recordSink{}
+	dedup := NewDedupSink(DedupConfig{Sink: sink, Window: time.Hour})
+	defer dedup.Close()
+	logService := NewService(Service{Sinks: []Sink{dedup}})
+
+	for i := 0; i < 3; i++ {
+		logService.Error("db down")
+	}
+	logService.Error("db down")
+	logService.Warning("db down")
+
+	assert.Len(t, sink.entries, 3)
+	assert.Equal(t, LevelWarning, sink.entries[2].Level)
+}
+
+func TestDedupSink_Flush(t *testing.T) {
+	tests := []struct {
+		name    string
+		service Service
+		content string
+	}{
+		{
+			name:    "Flush with FormatText",
+			service: Service{NameApp: "api"},
+			content: "[api]-[ERROR] dedup_test.go:58:func1(): last message repeated 2 times user=fsandov",
+		},
+		{
+			name:    "Flush with FormatJSON",
+			service: Service{NameApp: "api", Format: FormatJSON},
+			content: `"message":"last message repeated 2 times"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &flushSink{}
+			dedup := NewDedupSink(DedupConfig{Sink: sink})
+			defer dedup.Close()
+			tt.service.Sinks = []Sink{dedup}
+			logService := NewService(tt.service).With(Any("user", "fsandov"))
+
+			for i := 0; i < 4; i++ {
+				if i == 3 {
+					logService.Flush()
+				}
+				logService.Error("db down")
+			}
+
+			assert.Len(t, sink.entries, 2)
+			assert.Equal(t, 2, sink.flushed)
+			assert.Equal(t, "last message repeated 2 times", sink.entries[1].Message)
+			assert.Equal(t, LevelError, sink.entries[1].Level)
+			assert.Contains(t, sink.entries[1].Content, tt.content)
+			assert.NoError(t, dedup.Close())
+			assert.Len(t, sink.entries, 3)
+			assert.Equal(t, "last message repeated 1 times", sink.entries[2].Message)
+		})
+	}
+}
+
+func TestDedupSink_summary(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter Formatter
+		content   string
+	}{
+		{
+			name:    "summary when the message is in the file name",
+			content: "[dedup]-[ERROR] dedup_test.go:96:func1(): last message repeated 1 times",
+		},
+		{
+			name:      "summary with the Formatter",
+			formatter: Formatter{ShowDate: true},
+			content:   "[" + time.Now().Format("2006-01-02") + "][dedup]-[ERROR] dedup_test.go:96:func1(): last message repeated 1 times",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordSink{}
+			dedup := NewDedupSink(DedupConfig{Sink: sink, Formatter: tt.formatter})
+			logService := NewService(Service{NameApp: "dedup", Sinks: []Sink{dedup}})
+
+			for i := 0; i < 2; i++ {
+				logService.Error("dedup")
+			}
+			assert.NoError(t, dedup.Close())
+
+			assert.Len(t, sink.entries, 2)
+			assert.Equal(t, tt.content, sink.entries[1].Content)
+		})
+	}
+}
+
+func TestDedupSink_expire(t *testing.T) {
+	sink := &recordSink{}
+	dedup := NewDedupSink(DedupConfig{Sink: sink, Window: 20 * time.Millisecond})
+	defer dedup.Close()
+	logService := NewService(Service{Sinks: []Sink{dedup}})
+
+	logService.Error("db down")
+	logService.Error("db down")
+	assert.Eventually(t, func() bool {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		return len(sink.entries) == 2
+	}, time.Second, 5*time.Millisecond)
+	logService.Error("db down")
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	assert.Len(t, sink.entries, 3)
+	assert.Equal(t, "db down", sink.entries[2].Message)
+}
+
+func TestService_URLDedup(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	logService := NewService(Service{URL: server.URL, URLDedup: time.Hour})
+
+	for i := 0; i < 5; i++ {
+		logService.Error("db down")
+	}
+	assert.Equal(t, int32(1), requests.Load())
+	logService.Flush()
+	assert.Equal(t, int32(2), requests.Load())
+}
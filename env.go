@@ -0,0 +1,298 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultPrefix is the prefix of the environment variables of the DefaultService.
+const defaultPrefix = "LOGS"
+
+// callerFormats are the names of the CallerFormat values accepted in the environment variables.
+var callerFormats = map[string]CallerFormat{
+	"file":     CallerFileName,
+	"short":    CallerShortPath,
+	"full":     CallerFullPath,
+	"function": CallerFunctionName,
+}
+
+// NewServiceFromEnv returns a new instance of a Service configured with the environment variables with the prefix
+// provided. If the prefix is not provided, it will be "LOGS". The variables that are not set keep the default values
+// of NewService. It returns all the errors of the variables that are not valid.
+//
+// The variables of the service are:
+//   - LOGS_APP: the NameApp.
+//   - LOGS_LEVEL: the MinLevel. Example: debug
+//   - LOGS_WEBHOOK_URL: the URL of the webhook.
+//   - LOGS_WEBHOOK_DEDUP: the URLDedup window. Example: 1m
+//   - LOGS_WEBHOOK_RATE_LIMIT and LOGS_WEBHOOK_OVERFLOW: the rate and the overflow of the URLRateLimit.
+//     Example: 30/1m and digest
+//   - LOGS_FILE: true to save the logs in a file. It is true when LOGS_DIR is set.
+//   - LOGS_DIR: the Dir of the file.
+//   - LOGS_FORMAT: text, json or pretty.
+//   - LOGS_SHOW_DATE and LOGS_SHOW_TIME: true to show the date and the time.
+//   - LOGS_CALLER_FORMAT: file, short, full or function.
+//   - LOGS_STACK_LEVEL and LOGS_RECOVER_LEVEL: the StackLevel and the RecoverLevel.
+//   - LOGS_REPANIC: true to panic again after the panics are logged by Recover.
+//   - LOGS_EXIT_CODE: the ExitCode of Fatal.
+//   - LOGS_REDACT: true to redact the sensitive values with all the detectors and the DefaultRedactedFields.
+//   - LOGS_REDACT_MASK and LOGS_REDACT_HASH_KEY: the Mask and the HashKey of the redaction. Example: partial
+//
+// The sinks are added when their address is set:
+//   - LOGS_SPLUNK_URL, LOGS_SPLUNK_TOKEN, LOGS_SPLUNK_INDEX, LOGS_SPLUNK_SOURCETYPE, LOGS_SPLUNK_BATCH_SIZE and
+//     LOGS_SPLUNK_FLUSH_INTERVAL for the SplunkSink.
+//   - LOGS_GELF_ADDRESS, LOGS_GELF_NETWORK and LOGS_GELF_COMPRESSION for the GELFSink.
+//   - LOGS_OTLP_ENDPOINT, LOGS_OTLP_ENCODING, LOGS_OTLP_HEADERS, LOGS_OTLP_BATCH_SIZE and LOGS_OTLP_FLUSH_INTERVAL
+//     for the OTLPSink. The headers are a list of key=value separated by commas.
+func NewServiceFromEnv(prefix string) (Service, error) {
+	return serviceFromEnv(prefix, Service{})
+}
+
+// DefaultServiceError returns the error of the environment variables of the DefaultService, or nil when they are
+// valid. The DefaultService ignores the variables when they are not valid and nothing is printed, so the application
+// can check this error when it starts.
+func DefaultServiceError() error {
+	return defaultServiceErr
+}
+
+// defaultService returns the DefaultService, with the default configuration changed by the environment variables,
+// and the error of the variables. When the variables are not valid, the default configuration is used.
+func defaultService() (Service, error) {
+	config := Service{NameApp: "LOGS", FileLog: true}
+	service, err := serviceFromEnv(defaultPrefix, config)
+	if err != nil {
+		return NewService(config), err
+	}
+	return service, nil
+}
+
+// serviceFromEnv returns a new instance of a Service with the configuration provided, changed by the environment
+// variables with the prefix provided.
+func serviceFromEnv(prefix string, config Service) (Service, error) {
+	if prefix == "" {
+		prefix = defaultPrefix
+	}
+	env := envReader{prefix: strings.TrimSuffix(prefix, "_") + "_"}
+	env.string("APP", &config.NameApp)
+	env.level("LEVEL", &config.MinLevel)
+	env.url("WEBHOOK_URL", &config.URL)
+	env.duration("WEBHOOK_DEDUP", &config.URLDedup)
+	if key, value, ok := env.lookup("WEBHOOK_RATE_LIMIT"); ok {
+		rate, per, err := parseRate(value)
+		if err != nil {
+			env.fail(key, value, err)
+		}
+		config.URLRateLimit = &RateLimitConfig{Rate: rate, Per: per}
+		env.oneOf("WEBHOOK_OVERFLOW", (*string)(&config.URLRateLimit.Overflow), string(RateLimitDrop), string(RateLimitDigest))
+	}
+	if env.string("DIR", &config.Dir) {
+		config.FileLog = true
+	}
+	env.bool("FILE", &config.FileLog)
+	env.oneOf("FORMAT", (*string)(&config.Format), string(FormatText), string(FormatJSON), string(FormatPretty))
+	env.bool("SHOW_DATE", &config.ShowDate)
+	env.bool("SHOW_TIME", &config.ShowTime)
+	if key, value, ok := env.lookup("CALLER_FORMAT"); ok {
+		callerFormat, found := callerFormats[strings.ToLower(value)]
+		if !found {
+			env.fail(key, value, errors.New("it must be file, short, full or function"))
+		}
+		config.CallerFormat = callerFormat
+	}
+	env.level("STACK_LEVEL", &config.StackLevel)
+	env.level("RECOVER_LEVEL", &config.RecoverLevel)
+	env.bool("REPANIC", &config.RePanic)
+	env.int("EXIT_CODE", &config.ExitCode)
+	var redact bool
+	env.bool("REDACT", &redact)
+	if redact {
+		config.Redaction = &RedactionConfig{}
+		env.oneOf("REDACT_MASK", (*string)(&config.Redaction.Mask), string(MaskFull), string(MaskPartial), string(MaskHash))
+		var hashKey string
+		if env.string("REDACT_HASH_KEY", &hashKey) {
+			config.Redaction.HashKey = []byte(hashKey)
+		} else if config.Redaction.Mask == MaskHash {
+			env.failSecret(env.prefix+"REDACT_HASH_KEY", errors.New("it is required with "+env.prefix+"REDACT_MASK=hash"))
+		}
+	}
+
+	var splunk SplunkConfig
+	if env.url("SPLUNK_URL", &splunk.URL) {
+		if !env.string("SPLUNK_TOKEN", &splunk.Token) {
+			env.failSecret(env.prefix+"SPLUNK_TOKEN", errors.New("it is required with "+env.prefix+"SPLUNK_URL"))
+		}
+		env.string("SPLUNK_INDEX", &splunk.Index)
+		env.string("SPLUNK_SOURCETYPE", &splunk.SourceType)
+		env.int("SPLUNK_BATCH_SIZE", &splunk.BatchSize)
+		env.duration("SPLUNK_FLUSH_INTERVAL", &splunk.FlushInterval)
+	}
+	var gelf GELFConfig
+	if env.string("GELF_ADDRESS", &gelf.Address) {
+		env.oneOf("GELF_NETWORK", &gelf.Network, "udp", "tcp")
+		env.oneOf("GELF_COMPRESSION", &gelf.Compression, GELFCompressionGzip, GELFCompressionZlib, GELFCompressionNone)
+	}
+	var otlp OTLPConfig
+	if env.url("OTLP_ENDPOINT", &otlp.Endpoint) {
+		env.oneOf("OTLP_ENCODING", &otlp.Encoding, OTLPEncodingProtobuf, OTLPEncodingJSON)
+		env.headers("OTLP_HEADERS", &otlp.Headers)
+		env.int("OTLP_BATCH_SIZE", &otlp.BatchSize)
+		env.duration("OTLP_FLUSH_INTERVAL", &otlp.FlushInterval)
+	}
+	if len(env.errs) > 0 {
+		return Service{}, errors.Join(env.errs...)
+	}
+
+	config.Sinks = config.Sinks[:len(config.Sinks):len(config.Sinks)]
+	if splunk.URL != "" {
+		config.Sinks = append(config.Sinks, NewSplunkSink(splunk))
+	}
+	if gelf.Address != "" {
+		config.Sinks = append(config.Sinks, NewGELFSink(gelf))
+	}
+	if otlp.Endpoint != "" {
+		config.Sinks = append(config.Sinks, NewOTLPSink(otlp))
+	}
+	return NewService(config), nil
+}
+
+// envReader reads the environment variables with a prefix and keeps the errors of the values that are not valid.
+type envReader struct {
+	prefix string
+	errs   []error
+}
+
+// lookup returns the full name and the value of the variable. It returns false when the variable is not set or empty.
+func (e *envReader) lookup(name string) (string, string, bool) {
+	key := e.prefix + name
+	value := strings.TrimSpace(os.Getenv(key))
+	return key, value, value != ""
+}
+
+// fail keeps the error of the variable.
+func (e *envReader) fail(key string, value string, err error) {
+	e.errs = append(e.errs, fmt.Errorf("logs: invalid %s=%q: %w", key, value, err))
+}
+
+// failSecret keeps the error of a variable that may contain a secret, like the token of a URL or a header, without
+// its value.
+func (e *envReader) failSecret(key string, err error) {
+	e.errs = append(e.errs, fmt.Errorf("logs: invalid %s: %w", key, err))
+}
+
+// string sets the value of the variable in the target. It returns true when the variable is set.
+func (e *envReader) string(name string, target *string) bool {
+	_, value, ok := e.lookup(name)
+	if ok {
+		*target = value
+	}
+	return ok
+}
+
+// oneOf sets the value of the variable in the target, in lowercase, when it is one of the values provided.
+func (e *envReader) oneOf(name string, target *string, values ...string) {
+	key, value, ok := e.lookup(name)
+	if !ok {
+		return
+	}
+	for _, valid := range values {
+		if strings.EqualFold(value, valid) {
+			*target = valid
+			return
+		}
+	}
+	e.fail(key, value, fmt.Errorf("it must be one of %s", strings.Join(values, ", ")))
+}
+
+// bool sets the boolean value of the variable in the target. Example: true, false, 1 or 0
+func (e *envReader) bool(name string, target *bool) {
+	key, value, ok := e.lookup(name)
+	if !ok {
+		return
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		e.fail(key, value, errors.New("it must be true or false"))
+		return
+	}
+	*target = parsed
+}
+
+// int sets the positive integer value of the variable in the target.
+func (e *envReader) int(name string, target *int) {
+	key, value, ok := e.lookup(name)
+	if !ok {
+		return
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		e.fail(key, value, errors.New("it must be a positive integer"))
+		return
+	}
+	*target = parsed
+}
+
+// duration sets the positive duration value of the variable in the target. Example: 5s
+func (e *envReader) duration(name string, target *time.Duration) {
+	key, value, ok := e.lookup(name)
+	if !ok {
+		return
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		e.fail(key, value, errors.New("it must be a positive duration, like 5s"))
+		return
+	}
+	*target = parsed
+}
+
+// level sets the level of the variable in the target.
+func (e *envReader) level(name string, target *Level) {
+	key, value, ok := e.lookup(name)
+	if !ok {
+		return
+	}
+	level, err := ParseLevel(value)
+	if err != nil {
+		e.fail(key, value, errors.New("it must be trace, debug, info, notice, warning, error, panic or fatal"))
+		return
+	}
+	*target = level
+}
+
+// url sets the HTTP or HTTPS URL of the variable in the target. It returns true when the variable is set.
+func (e *envReader) url(name string, target *string) bool {
+	key, value, ok := e.lookup(name)
+	if !ok {
+		return false
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		e.failSecret(key, errors.New("it must be an http or https URL"))
+		return true
+	}
+	*target = value
+	return true
+}
+
+// headers sets the headers of the variable in the target, from a list of key=value separated by commas.
+func (e *envReader) headers(name string, target *map[string]string) {
+	key, value, ok := e.lookup(name)
+	if !ok {
+		return
+	}
+	headers := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		header, headerValue, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(header) == "" {
+			e.failSecret(key, errors.New("it must be a list of key=value separated by commas"))
+			return
+		}
+		headers[strings.TrimSpace(header)] = strings.TrimSpace(headerValue)
+	}
+	*target = headers
+}
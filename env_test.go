@@ -0,0 +1,150 @@
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewServiceFromEnv(t *testing.T) {
+	type want struct {
+		service Service
+		sinks   int
+		err     string
+	}
+	tests := []struct {
+		name   string
+		prefix string
+		env    map[string]string
+		want   want
+	}{
+		{
+			name: "NewServiceFromEnv without variables",
+			want: want{service: Service{NameApp: "LOGS", MinLevel: LevelTrace, Dir: "logs"}},
+		},
+		{
+			name:   "NewServiceFromEnv with the variables of the service",
+			prefix: "APP_",
+			env: map[string]string{
+				"APP_APP":                "api",
+				"APP_LEVEL":              "warn",
+				"APP_WEBHOOK_URL":        "https://discord.com/api/webhooks/1/a",
+				"APP_WEBHOOK_DEDUP":      "1m",
+				"APP_WEBHOOK_RATE_LIMIT": "30/1m",
+				"APP_WEBHOOK_OVERFLOW":   "digest",
+				"APP_DIR":                "/var/log/api",
+				"APP_FORMAT":             "JSON",
+				"APP_SHOW_DATE":          "true",
+				"APP_CALLER_FORMAT":      "short",
+				"APP_STACK_LEVEL":        "error",
+				"APP_EXIT_CODE":          "3",
+				"APP_REDACT":             "true",
+				"APP_REDACT_MASK":        "partial",
+			},
+			want: want{service: Service{
+				NameApp:      "api",
+				MinLevel:     LevelWarning,
+				URL:          "https://discord.com/api/webhooks/1/a",
+				URLDedup:     time.Minute,
+				URLRateLimit: &RateLimitConfig{Rate: 30, Per: time.Minute, Overflow: RateLimitDigest},
+				FileLog:      true,
+				Dir:          "/var/log/api",
+				Format:       FormatJSON,
+				ShowDate:     true,
+				CallerFormat: CallerShortPath,
+				StackLevel:   LevelError,
+				ExitCode:     3,
+				Redaction:    &RedactionConfig{Mask: MaskPartial},
+			}},
+		},
+		{
+			name: "NewServiceFromEnv with the variables of the sinks",
+			env: map[string]string{
+				"LOGS_SPLUNK_URL":      "https://splunk.example.com:8088",
+				"LOGS_SPLUNK_TOKEN":    "token",
+				"LOGS_GELF_ADDRESS":    "graylog.example.com:12201",
+				"LOGS_OTLP_ENDPOINT":   "http://collector:4318/v1/logs",
+				"LOGS_OTLP_HEADERS":    "authorization=Bearer token",
+				"LOGS_OTLP_ENCODING":   "json",
+				"LOGS_GELF_NETWORK":    "tcp",
+				"LOGS_SPLUNK_INDEX":    "main",
+				"LOGS_OTLP_BATCH_SIZE": "10",
+			},
+			want: want{service: Service{NameApp: "LOGS", MinLevel: LevelTrace, Dir: "logs"}, sinks: 3},
+		},
+		{
+			name: "NewServiceFromEnv with invalid variables",
+			env: map[string]string{
+				"LOGS_LEVEL":              "verbose",
+				"LOGS_SHOW_TIME":          "sometimes",
+				"LOGS_WEBHOOK_URL":        "discord.com/api/webhooks/1/secret",
+				"LOGS_OTLP_ENDPOINT":      "http://collector:4318/v1/logs",
+				"LOGS_OTLP_HEADERS":       "Authorization Bearer secret",
+				"LOGS_WEBHOOK_RATE_LIMIT": "fast",
+				"LOGS_SPLUNK_URL":         "https://splunk.example.com:8088",
+			},
+			want: want{err: `logs: invalid LOGS_LEVEL="verbose": it must be trace, debug, info, notice, warning, error, panic or fatal
+logs: invalid LOGS_WEBHOOK_URL: it must be an http or https URL
+logs: invalid LOGS_WEBHOOK_RATE_LIMIT="fast": it must be a rate like 30/1m
+logs: invalid LOGS_SHOW_TIME="sometimes": it must be true or false
+logs: invalid LOGS_SPLUNK_TOKEN: it is required with LOGS_SPLUNK_URL
+logs: invalid LOGS_OTLP_HEADERS: it must be a list of key=value separated by commas`},
+		},
+		{
+			name: "NewServiceFromEnv with the hash mask without a key",
+			env:  map[string]string{"LOGS_REDACT": "true", "LOGS_REDACT_MASK": "hash"},
+			want: want{err: `logs: invalid LOGS_REDACT_HASH_KEY: it is required with LOGS_REDACT_MASK=hash`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			service, err := NewServiceFromEnv(tt.prefix)
+
+			if tt.want.err != "" {
+				assert.EqualError(t, err, tt.want.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, service.Sinks, tt.want.sinks)
+			for _, sink := range service.Sinks {
+				if closer, ok := sink.(interface{ Close() error }); ok {
+					_ = closer.Close()
+				}
+			}
+			assert.Equal(t, tt.want.service.NameApp, service.NameApp)
+			assert.Equal(t, tt.want.service.MinLevel, service.CurrentLevel())
+			assert.Equal(t, tt.want.service.URL, service.URL)
+			assert.Equal(t, tt.want.service.URLDedup, service.URLDedup)
+			assert.Equal(t, tt.want.service.URLRateLimit, service.URLRateLimit)
+			assert.Equal(t, tt.want.service.Redaction, service.Redaction)
+			assert.Equal(t, tt.want.service.FileLog, service.FileLog)
+			assert.Equal(t, tt.want.service.Dir, service.Dir)
+			assert.Equal(t, tt.want.service.Format, service.Format)
+			assert.Equal(t, tt.want.service.ShowDate, service.ShowDate)
+			assert.Equal(t, tt.want.service.CallerFormat, service.CallerFormat)
+			assert.Equal(t, tt.want.service.StackLevel, service.StackLevel)
+			if tt.want.service.ExitCode != 0 {
+				assert.Equal(t, tt.want.service.ExitCode, service.ExitCode)
+			}
+		})
+	}
+}
+
+func Test_defaultService(t *testing.T) {
+	t.Setenv("LOGS_APP", "api")
+	t.Setenv("LOGS_FILE", "false")
+	service, err := defaultService()
+	assert.NoError(t, err)
+	assert.Equal(t, "api", service.NameApp)
+	assert.False(t, service.FileLog)
+
+	t.Setenv("LOGS_FORMAT", "xml")
+	service, err = defaultService()
+	assert.EqualError(t, err, `logs: invalid LOGS_FORMAT="xml": it must be one of text, json, pretty`)
+	assert.Equal(t, "LOGS", service.NameApp)
+	assert.True(t, service.FileLog)
+}
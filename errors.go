@@ -0,0 +1,177 @@
+package logs
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+const (
+	// ErrorField is the key of the field returned by Err.
+	ErrorField = "error"
+	// errorMaxDepth is the maximum depth of the chain of errors that is recorded.
+	errorMaxDepth = 32
+)
+
+// LogFielder is implemented by the errors that attach their own fields to the logs, like the IDs of a domain error.
+type LogFielder interface {
+	// LogFields returns the fields of the error.
+	LogFields() []Field
+}
+
+// ErrorValue is the value of the field returned by Err. It records the message and the Go type of an error, the errors
+// it wraps, the stack trace of the errors that carry one and the fields of the errors that implement LogFielder.
+type ErrorValue struct {
+	// Message is the message of the error.
+	Message string `json:"message"`
+	// Type is the Go type of the error. Example: *fs.PathError
+	Type string `json:"type"`
+	// Causes are the errors wrapped by the error, with errors.Unwrap or with errors.Join.
+	Causes []ErrorValue `json:"causes,omitempty"`
+	// Stack is the stack trace carried by the error, like the ones of github.com/pkg/errors.
+	Stack []Frame `json:"stack,omitempty"`
+	// Fields are the fields of the error when it implements LogFielder.
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+// Err returns a Field with the details of the error. Example: service.With(logs.Err(err)).Error("db down")
+func Err(err error) Field {
+	return Field{Key: ErrorField, Value: newErrorValue(err, 0)}
+}
+
+// String returns the message of the error. It is how the error is printed in the text logs.
+func (e ErrorValue) String() string {
+	return e.Message
+}
+
+// newErrorValue returns the details of the error and of the errors it wraps, until the errorMaxDepth.
+func newErrorValue(err error, depth int) ErrorValue {
+	if err == nil {
+		return ErrorValue{Message: "<nil>", Type: "<nil>"}
+	}
+	value := ErrorValue{
+		Message: err.Error(),
+		Type:    fmt.Sprintf("%T", err),
+		Stack:   errorStack(err),
+	}
+	if fielder, ok := err.(LogFielder); ok {
+		value.Fields = fieldsMap(fielder.LogFields())
+	}
+	if depth >= errorMaxDepth {
+		return value
+	}
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, cause := range wrapper.Unwrap() {
+			if cause != nil {
+				value.Causes = append(value.Causes, newErrorValue(cause, depth+1))
+			}
+		}
+	case interface{ Unwrap() error }:
+		if cause := wrapper.Unwrap(); cause != nil {
+			value.Causes = append(value.Causes, newErrorValue(cause, depth+1))
+		}
+	}
+	return value
+}
+
+// errorStack returns the stack trace of the errors with a StackTrace method that returns program counters,
+// like the errors of github.com/pkg/errors. It is found with reflection, so that package is not required.
+func errorStack(err error) []Frame {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil
+	}
+	out := method.Type().Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	trace := method.Call(nil)[0]
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+	if len(pcs) == 0 {
+		return nil
+	}
+	var stack []Frame
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		if !more {
+			return stack
+		}
+	}
+}
+
+// flatFields returns the fields with the values of Err expanded into fields with simple values, for the outputs
+// that do not accept nested values. Example: error, error.type, error.causes, error.stack and error.<field>
+func flatFields(fields []Field) []Field {
+	var flat []Field
+	for _, field := range fields {
+		value, ok := field.Value.(ErrorValue)
+		if !ok {
+			flat = append(flat, field)
+			continue
+		}
+		flat = append(flat, Field{Key: field.Key, Value: value.Message}, Field{Key: field.Key + ".type", Value: value.Type})
+		if len(value.Causes) > 0 {
+			flat = append(flat, Field{Key: field.Key + ".causes", Value: strings.Join(errorCauses(value.Causes), "; ")})
+		}
+		if stack := value.stack(); len(stack) > 0 {
+			flat = append(flat, Field{Key: field.Key + ".stack", Value: stackText(stack)})
+		}
+		for _, errorField := range value.fields() {
+			flat = append(flat, Field{Key: field.Key + "." + errorField.Key, Value: errorField.Value})
+		}
+	}
+	return flat
+}
+
+// errorCauses returns the causes of an error, depth-first, as "message (type)".
+func errorCauses(causes []ErrorValue) []string {
+	var texts []string
+	for _, cause := range causes {
+		texts = append(texts, fmt.Sprintf("%s (%s)", cause.Message, cause.Type))
+		texts = append(texts, errorCauses(cause.Causes)...)
+	}
+	return texts
+}
+
+// stack returns the deepest stack trace of the error and its causes, which is the closest to the origin of the error.
+func (e ErrorValue) stack() []Frame {
+	for _, cause := range e.Causes {
+		if stack := cause.stack(); len(stack) > 0 {
+			return stack
+		}
+	}
+	return e.Stack
+}
+
+// fields returns the fields of the error and its causes. The fields of the outer errors have priority.
+func (e ErrorValue) fields() []Field {
+	var fields []Field
+	seen := map[string]bool{}
+	var walk func(value ErrorValue)
+	walk = func(value ErrorValue) {
+		keys := make([]string, 0, len(value.Fields))
+		for key := range value.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !seen[key] {
+				seen[key] = true
+				fields = append(fields, Field{Key: key, Value: value.Fields[key]})
+			}
+		}
+		for _, cause := range value.Causes {
+			walk(cause)
+		}
+	}
+	walk(e)
+	return fields
+}
@@ -0,0 +1,142 @@
+package logs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stackFrame and stackError mimic the errors of github.com/pkg/errors, which carry a StackTrace of program counters.
+type stackFrame uintptr
+
+type stackError struct {
+	message string
+	stack   []uintptr
+}
+
+func newStackError(message string) *stackError {
+	pcs := make([]uintptr, 8)
+	return &stackError{message: message, stack: pcs[:runtime.Callers(1, pcs)]}
+}
+
+func (e *stackError) Error() string { return e.message }
+
+func (e *stackError) StackTrace() []stackFrame {
+	frames := make([]stackFrame, len(e.stack))
+	for i, pc := range e.stack {
+		frames[i] = stackFrame(pc)
+	}
+	return frames
+}
+
+// domainError is an error that attaches its own fields to the logs.
+type domainError struct {
+	userID int
+}
+
+func (e domainError) Error() string { return "user not found" }
+
+func (e domainError) LogFields() []Field {
+	return []Field{Any("user_id", e.userID), Any("retryable", false)}
+}
+
+func TestErr(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "config.yml", Err: fs.ErrNotExist}
+	type want struct {
+		value ErrorValue
+	}
+	tests := []struct {
+		name string
+		err  error
+		want want
+	}{
+		{
+			name: "Err with a simple error",
+			err:  errors.New("db down"),
+			want: want{value: ErrorValue{Message: "db down", Type: "*errors.errorString"}},
+		},
+		{
+			name: "Err with a wrapped error",
+			err:  fmt.Errorf("loading: %w", pathErr),
+			want: want{value: ErrorValue{
+				Message: "loading: open config.yml: file does not exist",
+				Type:    "*fmt.wrapError",
+				Causes: []ErrorValue{{
+					Message: "open config.yml: file does not exist",
+					Type:    "*fs.PathError",
+					Causes:  []ErrorValue{{Message: "file does not exist", Type: "*errors.errorString"}},
+				}},
+			}},
+		},
+		{
+			name: "Err with joined errors",
+			err:  errors.Join(errors.New("first"), domainError{userID: 7}),
+			want: want{value: ErrorValue{
+				Message: "first\nuser not found",
+				Type:    "*errors.joinError",
+				Causes: []ErrorValue{
+					{Message: "first", Type: "*errors.errorString"},
+					{Message: "user not found", Type: "logs.domainError", Fields: map[string]interface{}{"user_id": 7, "retryable": false}},
+				},
+			}},
+		},
+		{
+			name: "Err with a nil error",
+			err:  nil,
+			want: want{value: ErrorValue{Message: "<nil>", Type: "<nil>"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := Err(tt.err)
+
+			assert.Equal(t, ErrorField, field.Key)
+			assert.Equal(t, tt.want.value, field.Value)
+		})
+	}
+}
+
+func TestErr_stack(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", newStackError("origin"))
+	value := Err(err).Value.(ErrorValue)
+
+	assert.Nil(t, value.Stack)
+	assert.Equal(t, "github.com/fsandov/logs.newStackError", value.Causes[0].Stack[0].Function)
+	assert.Equal(t, "github.com/fsandov/logs.TestErr_stack", value.Causes[0].Stack[1].Function)
+}
+
+func Test_flatFields(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", errors.Join(newStackError("origin"), domainError{userID: 7}))
+	fields := flatFields([]Field{Any("user", "fsandov"), Err(err)})
+
+	keys := make([]string, 0, len(fields))
+	for _, field := range fields {
+		keys = append(keys, field.Key)
+	}
+	assert.Equal(t, []string{"user", "error", "error.type", "error.causes", "error.stack", "error.retryable", "error.user_id"}, keys)
+	assert.Equal(t, "origin\nuser not found (*errors.joinError); origin (*logs.stackError); user not found (logs.domainError)", fields[3].Value)
+	assert.True(t, strings.HasPrefix(fields[4].Value.(string), "github.com/fsandov/logs.newStackError()"))
+}
+
+func TestService_WithErr(t *testing.T) {
+	sink := &recordSink{}
+	logService := NewService(Service{Format: FormatJSON, Sinks: []Sink{sink}})
+	logService.With(Err(fmt.Errorf("query: %w", errors.New("db down")))).Error("message")
+
+	entry := sink.entries[0]
+	assert.True(t, strings.HasSuffix(NewService(Service{}).logContent(entry, "message"), `: message error="query: db down"`))
+	var value struct {
+		Fields struct {
+			Error ErrorValue `json:"error"`
+		} `json:"fields"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(entry.Content), &value))
+	assert.Equal(t, "*fmt.wrapError", value.Fields.Error.Type)
+	assert.Equal(t, "db down", value.Fields.Error.Causes[0].Message)
+}
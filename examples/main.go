@@ -12,13 +12,12 @@ func main() {
 	logsService.Notice("Hello World!")
 	logsService.Warning("Hello World!")
 	logsService.Error("Hello World!")
-	logsService.Fatal("Hello World!")
 	logs.Trace("Hello World!, from default service")
 	logs.Debug("Hello World!, from default service")
 	logs.Info("Hello World!, from default service")
 	logs.Notice("Hello World!, from default service")
 	logs.Warning("Hello World!, from default service")
 	logs.Error("Hello World!, from default service")
-	logs.Fatal("Hello World!, from default service")
-
+	// Fatal exits the application after the log is registered, so it is the last one.
+	logsService.Fatal("Hello World!")
 }
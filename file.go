@@ -0,0 +1,78 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileConfig is the struct that contains the configuration of the FileSink. Only the Path is required.
+type FileConfig struct {
+	// Path is the path of the file where the logs are saved. Its folder is created if it does not exist.
+	// Example: /var/log/app/app.log
+	Path string
+	// Formatter is the format of the logs. If it is not provided, the logs are saved in the format of the service.
+	Formatter Formatter
+}
+
+// FileSink is a sink that saves the logs in a file, one per line. The file is opened with the first log and it is kept
+// open until Close is called.
+type FileSink struct {
+	config FileConfig
+	mu     sync.Mutex
+	file   *os.File
+}
+
+// NewFileSink returns a new FileSink with the configuration provided.
+func NewFileSink(config FileConfig) *FileSink {
+	return &FileSink{config: config}
+}
+
+// Write saves the entry in the file.
+func (f *FileSink) Write(entry Entry) error {
+	content := f.config.Formatter.content(entry)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	start := time.Now()
+	if err := f.open(); err != nil {
+		metrics.delivered(sinkFile, 0, start, err)
+		return err
+	}
+	n, err := fmt.Fprintln(f.file, content)
+	metrics.delivered(sinkFile, n, start, err)
+	return err
+}
+
+// open opens the file if it is not open. It must be called with the lock.
+func (f *FileSink) open() error {
+	if f.file != nil {
+		return nil
+	}
+	if f.config.Path == "" {
+		return errors.New("file: the path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(f.config.Path), 0755); err != nil {
+		return fmt.Errorf("file: %w", err)
+	}
+	file, err := os.OpenFile(f.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return fmt.Errorf("file: %w", err)
+	}
+	f.file = file
+	return nil
+}
+
+// Close closes the file. It is opened again with the next log.
+func (f *FileSink) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
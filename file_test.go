@@ -0,0 +1,25 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSink_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "app.log")
+	sink := NewFileSink(FileConfig{Path: path})
+
+	require.NoError(t, sink.Write(Entry{Content: "first"}))
+	require.NoError(t, sink.Close())
+	require.NoError(t, sink.Write(Entry{Content: "second"}))
+	require.NoError(t, sink.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", string(content))
+	assert.EqualError(t, NewFileSink(FileConfig{}).Write(Entry{}), "file: the path is required")
+}
@@ -0,0 +1,79 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Format is the format of the logs printed in the console, saved in the file and sent to the URL.
+type Format string
+
+const (
+	// FormatText prints the logs as lines of text. Example: [LOGS]-[ERROR] main.go:12:run(): message
+	// It is the default format.
+	FormatText Format = "text"
+	// FormatJSON prints the logs as JSON objects, one per line.
+	FormatJSON Format = "json"
+	// FormatPretty prints the logs in the console for development, with colors per level, the fields aligned, and the
+	// lines of the messages and the stack traces indented. The colors are disabled when the console is not a terminal
+	// or the environment variable NO_COLOR is set. The file, the URL and the sinks that do not print in the console
+	// receive the logs in FormatText, without colors.
+	FormatPretty Format = "pretty"
+)
+
+// jsonEntry is the entry as it is printed with FormatJSON.
+type jsonEntry struct {
+	Time     time.Time              `json:"time"`
+	Level    Level                  `json:"level"`
+	App      string                 `json:"app"`
+	Message  string                 `json:"message"`
+	File     string                 `json:"file,omitempty"`
+	Line     int                    `json:"line,omitempty"`
+	Function string                 `json:"function,omitempty"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+	Stack    []Frame                `json:"stack,omitempty"`
+}
+
+// jsonContent returns the entry as a JSON object. The values of the fields that can not be marshaled are printed as text.
+func jsonContent(entry Entry) string {
+	value := jsonEntry{
+		Time:     entry.Time,
+		Level:    entry.Level,
+		App:      entry.NameApp,
+		Message:  entry.Message,
+		File:     entry.File,
+		Line:     entry.Line,
+		Function: entry.Function,
+		Fields:   fieldsMap(entry.Fields),
+		Stack:    entry.Stack,
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		for key, fieldValue := range value.Fields {
+			value.Fields[key] = fmt.Sprint(fieldValue)
+		}
+		content, _ = json.Marshal(value)
+	}
+	return string(content)
+}
+
+// Formatter is the format of the content of the entries written by the sinks that print text, like the FileSink.
+// If it is empty, the sinks write the Content of the entry, in the format of the service.
+type Formatter struct {
+	// Format is the format of the logs. If it is not provided, the Content of the entry is used.
+	Format Format
+	// ShowDate is a boolean that indicates if the date should be shown in the logs of FormatText.
+	ShowDate bool
+	// ShowTime is a boolean that indicates if the time should be shown in the logs of FormatText.
+	ShowTime bool
+}
+
+// content returns the content of the entry in the format of the formatter.
+func (f Formatter) content(entry Entry) string {
+	if f == (Formatter{}) {
+		return entry.Content
+	}
+	service := Service{NameApp: entry.NameApp, Format: f.Format, ShowDate: f.ShowDate, ShowTime: f.ShowTime}
+	return service.logContent(entry, entry.Message)
+}
@@ -0,0 +1,252 @@
+package logs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	// GELFCompressionGzip compresses the UDP messages with gzip. It is the default compression.
+	GELFCompressionGzip = "gzip"
+	// GELFCompressionZlib compresses the UDP messages with zlib.
+	GELFCompressionZlib = "zlib"
+	// GELFCompressionNone sends the UDP messages without compression.
+	GELFCompressionNone = "none"
+	// gelfChunkHeaderSize is the size of the header of a chunk: magic bytes, message ID, sequence number and count.
+	gelfChunkHeaderSize = 12
+	// gelfMaxChunks is the maximum number of chunks of a message accepted by Graylog.
+	gelfMaxChunks = 128
+)
+
+var (
+	gelfChunkMagic = []byte{0x1e, 0x0f}
+	// gelfInvalidKey matches the characters not allowed in the name of an additional field.
+	gelfInvalidKey = regexp.MustCompile(`[^\w.\-]`)
+)
+
+// GELFConfig is the struct that contains the configuration of the GELFSink. Only the Address is required.
+type GELFConfig struct {
+	// Address is the host and port of the Graylog input. Example: graylog.example.com:12201
+	Address string
+	// Network is the protocol used to send the messages, "udp" or "tcp". If it is not provided, it will be "udp".
+	Network string
+	// Compression is the compression of the UDP messages: GELFCompressionGzip, GELFCompressionZlib or GELFCompressionNone.
+	// If it is not provided, it will be GELFCompressionGzip. The TCP messages are never compressed.
+	Compression string
+	// ChunkSize is the maximum size of a UDP datagram. Bigger messages are chunked. If it is not provided, it will be 1420.
+	ChunkSize int
+	// Host is the host of the messages. If it is not provided, it will be the hostname of the machine.
+	Host string
+}
+
+// GELFSink is a sink that sends the logs to Graylog in the GELF 1.1 format, over UDP or TCP.
+type GELFSink struct {
+	config GELFConfig
+	mu     sync.Mutex
+	conn   net.Conn
+}
+
+// NewGELFSink returns a new GELFSink with the configuration provided. The connection is opened with the first log.
+func NewGELFSink(config GELFConfig) *GELFSink {
+	if config.Network == "" {
+		config.Network = "udp"
+	}
+	if config.Compression == "" {
+		config.Compression = GELFCompressionGzip
+	}
+	if config.ChunkSize <= gelfChunkHeaderSize {
+		config.ChunkSize = 1420
+	}
+	if config.Host == "" {
+		config.Host, _ = os.Hostname()
+	}
+	return &GELFSink{config: config}
+}
+
+// Write sends the entry to Graylog.
+func (g *GELFSink) Write(entry Entry) error {
+	message, err := json.Marshal(g.message(entry))
+	if err != nil {
+		return fmt.Errorf("gelf: error marshaling message: %w", err)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	start := time.Now()
+	if g.config.Network == "tcp" {
+		err = g.writeTCP(append(message, 0))
+	} else {
+		err = g.writeUDP(message)
+	}
+	metrics.delivered(sinkGELF, len(message), start, err)
+	return err
+}
+
+// Close closes the connection with Graylog.
+func (g *GELFSink) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.conn == nil {
+		return nil
+	}
+	err := g.conn.Close()
+	g.conn = nil
+	return err
+}
+
+// message returns the GELF message of the entry. The caller and the fields are sent as additional fields,
+// and the stack trace is sent in the full message.
+func (g *GELFSink) message(entry Entry) map[string]interface{} {
+	message := map[string]interface{}{
+		"version":       "1.1",
+		"host":          g.config.Host,
+		"short_message": entry.Message,
+		"timestamp":     float64(entry.Time.UnixMilli()) / 1000,
+		"level":         entry.Level.syslogSeverity(),
+		"_app":          entry.NameApp,
+		"_level_name":   string(entry.Level),
+	}
+	if entry.Message == "" {
+		message["short_message"] = "-"
+	}
+	if len(entry.Stack) > 0 {
+		message["full_message"] = entry.Message + "\n" + stackText(entry.Stack)
+	}
+	if entry.File != "" {
+		message["_file"] = entry.File
+		message["_line"] = entry.Line
+		message["_function"] = entry.Function
+	}
+	for _, field := range flatFields(entry.Fields) {
+		message[gelfKey(field.Key)] = field.Value
+	}
+	return message
+}
+
+// writeUDP compresses the message and sends it in one datagram, or in chunks when it is bigger than the ChunkSize.
+func (g *GELFSink) writeUDP(message []byte) error {
+	data, err := g.compress(message)
+	if err != nil {
+		return err
+	}
+	if err := g.connect(); err != nil {
+		return err
+	}
+	if len(data) <= g.config.ChunkSize {
+		_, err = g.conn.Write(data)
+		return err
+	}
+	chunkData := g.config.ChunkSize - gelfChunkHeaderSize
+	count := (len(data) + chunkData - 1) / chunkData
+	if count > gelfMaxChunks {
+		return fmt.Errorf("gelf: message too big, it needs %d chunks", count)
+	}
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * chunkData
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk := make([]byte, 0, gelfChunkHeaderSize+end-i*chunkData)
+		chunk = append(chunk, gelfChunkMagic...)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, data[i*chunkData:end]...)
+		if _, err := g.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTCP sends the null-byte-delimited message. It connects again once if the connection was lost.
+func (g *GELFSink) writeTCP(message []byte) error {
+	for attempt := 0; ; attempt++ {
+		if err := g.connect(); err != nil {
+			return err
+		}
+		_, err := g.conn.Write(message)
+		if err == nil || attempt > 0 {
+			return err
+		}
+		_ = g.conn.Close()
+		g.conn = nil
+	}
+}
+
+// connect opens the connection with Graylog if it is not open.
+func (g *GELFSink) connect() error {
+	if g.conn != nil {
+		return nil
+	}
+	conn, err := net.Dial(g.config.Network, g.config.Address)
+	if err != nil {
+		return fmt.Errorf("gelf: %w", err)
+	}
+	g.conn = conn
+	return nil
+}
+
+// compress returns the message compressed with the Compression of the configuration.
+func (g *GELFSink) compress(message []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	switch g.config.Compression {
+	case GELFCompressionNone:
+		return message, nil
+	case GELFCompressionZlib:
+		writer := zlib.NewWriter(&buffer)
+		if _, err := writer.Write(message); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+	default:
+		writer := gzip.NewWriter(&buffer)
+		if _, err := writer.Write(message); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return buffer.Bytes(), nil
+}
+
+// gelfKey returns the name of the additional field of the key. The name "_id" is reserved by Graylog.
+func gelfKey(key string) string {
+	key = "_" + gelfInvalidKey.ReplaceAllString(key, "_")
+	if key == "_id" {
+		return "__id"
+	}
+	return key
+}
+
+// syslogSeverity returns the syslog severity of the level, from 7 for debug to 2 for critical.
+func (l Level) syslogSeverity() int {
+	switch l {
+	case LevelTrace, LevelDebug:
+		return 7
+	case LevelInfo:
+		return 6
+	case LevelNotice:
+		return 5
+	case LevelWarning:
+		return 4
+	case LevelError:
+		return 3
+	case LevelPanic, LevelFatal:
+		return 2
+	default:
+		return 6
+	}
+}
@@ -0,0 +1,174 @@
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readGELFUDP reads the datagrams of a message from the connection, joins the chunks and decompresses it.
+func readGELFUDP(t *testing.T, conn net.PacketConn, compression string) map[string]interface{} {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buffer := make([]byte, 65535)
+	var chunks [][]byte
+	for {
+		n, _, err := conn.ReadFrom(buffer)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		packet := append([]byte(nil), buffer[:n]...)
+		if !bytes.HasPrefix(packet, gelfChunkMagic) {
+			chunks = [][]byte{packet}
+			break
+		}
+		chunks = append(chunks, packet)
+		if len(chunks) == int(packet[11]) {
+			sort.Slice(chunks, func(i, j int) bool { return chunks[i][10] < chunks[j][10] })
+			for i := range chunks {
+				chunks[i] = chunks[i][gelfChunkHeaderSize:]
+			}
+			break
+		}
+	}
+	data := bytes.Join(chunks, nil)
+	var reader io.Reader = bytes.NewReader(data)
+	switch compression {
+	case GELFCompressionGzip:
+		gz, err := gzip.NewReader(reader)
+		assert.NoError(t, err)
+		reader = gz
+	case GELFCompressionZlib:
+		zl, err := zlib.NewReader(reader)
+		assert.NoError(t, err)
+		reader = zl
+	}
+	var message map[string]interface{}
+	assert.NoError(t, json.NewDecoder(reader).Decode(&message))
+	return message
+}
+
+func TestGELFSink_WriteUDP(t *testing.T) {
+	type args struct {
+		compression string
+		message     string
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{
+			name: "GELFSink sends a gzip message in one datagram",
+			args: args{compression: GELFCompressionGzip, message: "message"},
+		},
+		{
+			name: "GELFSink sends a zlib message in one datagram",
+			args: args{compression: GELFCompressionZlib, message: "message"},
+		},
+		{
+			name: "GELFSink chunks a big message",
+			args: args{compression: GELFCompressionNone, message: strings.Repeat("message ", 500)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			assert.NoError(t, err)
+			defer conn.Close()
+			sink := NewGELFSink(GELFConfig{
+				Address:     conn.LocalAddr().String(),
+				Compression: tt.args.compression,
+				ChunkSize:   512,
+				Host:        "host",
+			})
+			defer sink.Close()
+			err = sink.Write(Entry{
+				Time:     time.Unix(1700000000, 500000000),
+				Level:    LevelError,
+				NameApp:  "Test",
+				Message:  tt.args.message,
+				File:     "main.go",
+				Line:     12,
+				Function: "main",
+				Fields:   []Field{Any("user", "fsandov"), Any("id", 1)},
+			})
+			assert.NoError(t, err)
+
+			message := readGELFUDP(t, conn, tt.args.compression)
+			assert.Equal(t, "1.1", message["version"])
+			assert.Equal(t, "host", message["host"])
+			assert.Equal(t, tt.args.message, message["short_message"])
+			assert.Equal(t, 1700000000.5, message["timestamp"])
+			assert.Equal(t, float64(3), message["level"])
+			assert.Equal(t, "main.go", message["_file"])
+			assert.Equal(t, float64(12), message["_line"])
+			assert.Equal(t, "fsandov", message["_user"])
+			assert.Equal(t, float64(1), message["__id"])
+		})
+	}
+}
+
+func TestGELFSink_WriteTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	sink := NewGELFSink(GELFConfig{Address: listener.Addr().String(), Network: "tcp"})
+	defer sink.Close()
+	assert.NoError(t, sink.Write(Entry{Level: LevelInfo, Message: "first"}))
+	assert.NoError(t, sink.Write(Entry{Level: LevelWarning, Message: "second"}))
+
+	conn, err := listener.Accept()
+	assert.NoError(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for _, want := range []struct {
+		message string
+		level   float64
+	}{{"first", 6}, {"second", 4}} {
+		data, err := reader.ReadBytes(0)
+		assert.NoError(t, err)
+		var message map[string]interface{}
+		assert.NoError(t, json.Unmarshal(data[:len(data)-1], &message))
+		assert.Equal(t, want.message, message["short_message"])
+		assert.Equal(t, want.level, message["level"])
+	}
+}
+
+func Test_gelfKey(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{name: "gelfKey with a valid key", key: "request.id", want: "_request.id"},
+		{name: "gelfKey with invalid characters", key: "user name", want: "_user_name"},
+		{name: "gelfKey with the reserved id", key: "id", want: "__id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, gelfKey(tt.key))
+		})
+	}
+}
+
+func TestService_With(t *testing.T) {
+	logService := NewService(Service{})
+	child := logService.With(Any("user", "fsandov"))
+	grandChild := child.With(Any("message", "hello world"))
+
+	assert.Equal(t, "[LOGS]-[INFO] message ", logService.logBuilder(LevelInfo, "message").Content)
+	assert.Equal(t, "[LOGS]-[INFO] message user=fsandov", child.logBuilder(LevelInfo, "message").Content)
+	entry := grandChild.logBuilder(LevelInfo, "message")
+	assert.Equal(t, `[LOGS]-[INFO] message user=fsandov message="hello world"`, entry.Content)
+	assert.Equal(t, []Field{Any("user", "fsandov"), Any("message", "hello world")}, entry.Fields)
+}
@@ -0,0 +1,24 @@
+module github.com/fsandov/logs/grpclogs
+
+go 1.25.0
+
+require (
+	github.com/fsandov/logs v0.0.0-20261018224513-677b07f9d21a
+	github.com/stretchr/testify v1.8.2
+	google.golang.org/grpc v1.82.1
+	google.golang.org/protobuf v1.36.11
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.53.0 // indirect
+	golang.org/x/sys v0.43.0 // indirect
+	golang.org/x/text v0.36.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260414002931-afd174a4e478 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The replace is used only to develop the interceptors with the core module of this repository. The consumers
+// of the module ignore it and use the version required above.
+replace github.com/fsandov/logs => ../
@@ -0,0 +1,331 @@
+// Package grpclogs provides the gRPC interceptors that register the logs of the calls through a logs.Service.
+// It is a separate module, so the core module does not require gRPC.
+package grpclogs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/fsandov/logs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// RequestIDKey is the default metadata key of the request ID.
+	RequestIDKey = "x-request-id"
+	// traceparentKey is the metadata key of the W3C trace context.
+	traceparentKey = "traceparent"
+)
+
+// Config is the struct that contains the configuration of the interceptors. All the fields are optional.
+type Config struct {
+	// RequestIDKey is the metadata key where the request ID is received, returned and propagated.
+	// If it is not provided, it will be RequestIDKey.
+	RequestIDKey string
+	// Level returns the level of the log of a call with the code provided. If it is not provided, it will be DefaultLevel.
+	// The levels Panic and Fatal are registered as Error, so the interceptors never stop the application.
+	Level func(code codes.Code) logs.Level
+}
+
+// DefaultLevel returns the level of the log of a call with the code provided. The codes caused by the client are Info,
+// the codes that may be temporary are Warning and the codes of the bugs of the server are Error.
+func DefaultLevel(code codes.Code) logs.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.Unauthenticated:
+		return logs.LevelInfo
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted, codes.FailedPrecondition,
+		codes.Aborted, codes.OutOfRange, codes.Unavailable:
+		return logs.LevelWarning
+	default:
+		return logs.LevelError
+	}
+}
+
+// UnaryServerInterceptor returns a server interceptor that registers one log for every unary call, with the method,
+// peer, code, duration and sizes of the messages. The handlers receive in the context a child of the service with the
+// request ID of the metadata, or a new one, that is returned by logs.FromContext.
+func UnaryServerInterceptor(service logs.Service, config Config) grpc.UnaryServerInterceptor {
+	config = newConfig(config)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx, child := config.serverContext(ctx, service)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(config.RequestIDKey, logs.RequestIDFromContext(ctx)))
+
+		resp, err := handler(ctx, req)
+
+		config.log(child, call{
+			kind:         "server",
+			method:       info.FullMethod,
+			peer:         peerAddress(ctx),
+			err:          err,
+			duration:     time.Since(start),
+			sentSize:     messageSize(resp),
+			receivedSize: messageSize(req),
+		})
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor that registers one log for every stream when it ends, like
+// UnaryServerInterceptor. The sizes are the totals of the messages of the stream.
+func StreamServerInterceptor(service logs.Service, config Config) grpc.StreamServerInterceptor {
+	config = newConfig(config)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, child := config.serverContext(ss.Context(), service)
+		_ = ss.SetHeader(metadata.Pairs(config.RequestIDKey, logs.RequestIDFromContext(ctx)))
+		stream := &serverStream{ServerStream: ss, ctx: ctx}
+
+		err := handler(srv, stream)
+
+		config.log(child, call{
+			kind:         "server",
+			method:       info.FullMethod,
+			peer:         peerAddress(ctx),
+			err:          err,
+			duration:     time.Since(start),
+			sentSize:     stream.sentSize,
+			receivedSize: stream.receivedSize,
+		})
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns a client interceptor that registers one log for every unary call, with the method,
+// target, code, duration and sizes of the messages. The request ID of the context is propagated in the metadata.
+func UnaryClientInterceptor(service logs.Service, config Config) grpc.UnaryClientInterceptor {
+	config = newConfig(config)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		ctx = config.clientContext(ctx)
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		config.log(service.WithContext(ctx), call{
+			kind:         "client",
+			method:       method,
+			peer:         cc.Target(),
+			err:          err,
+			duration:     time.Since(start),
+			sentSize:     messageSize(req),
+			receivedSize: messageSize(reply),
+		})
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor that registers one log for every stream when it ends, like
+// UnaryClientInterceptor. The stream ends when a message cannot be received, or after the response of a stream
+// without server streaming.
+func StreamClientInterceptor(service logs.Service, config Config) grpc.StreamClientInterceptor {
+	config = newConfig(config)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		ctx = config.clientContext(ctx)
+		stream := &clientStream{
+			config:  config,
+			service: service.WithContext(ctx),
+			desc:    desc,
+			call:    call{kind: "client", method: method, peer: cc.Target()},
+			start:   start,
+		}
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			stream.finish(err)
+			return nil, err
+		}
+		stream.ClientStream = cs
+		return stream, nil
+	}
+}
+
+// call is the information of a gRPC call that is registered in its log.
+type call struct {
+	kind         string
+	method       string
+	peer         string
+	err          error
+	duration     time.Duration
+	sentSize     int
+	receivedSize int
+}
+
+// newConfig returns the configuration with the default values of the fields that are not provided.
+func newConfig(config Config) Config {
+	if config.RequestIDKey == "" {
+		config.RequestIDKey = RequestIDKey
+	}
+	if config.Level == nil {
+		config.Level = DefaultLevel
+	}
+	return config
+}
+
+// serverContext returns the context of the handler, with the request ID and the trace of the incoming metadata and
+// the child service, and the child service.
+func (c Config) serverContext(ctx context.Context, service logs.Service) (context.Context, logs.Service) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = logs.ContextWithRequestID(ctx, logs.RequestIDOrNew(firstValue(md, c.RequestIDKey)))
+	if traceparent := firstValue(md, traceparentKey); traceparent != "" {
+		ctx = logs.ContextWithTraceparent(ctx, traceparent)
+	}
+	child := service.WithContext(ctx)
+	return logs.NewContext(ctx, child), child
+}
+
+// clientContext returns the context with the request ID of the context in the outgoing metadata,
+// when the metadata does not have one.
+func (c Config) clientContext(ctx context.Context) context.Context {
+	requestID := logs.RequestIDFromContext(ctx)
+	if requestID == "" {
+		return ctx
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(c.RequestIDKey)) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, c.RequestIDKey, requestID)
+}
+
+// log registers the log of the call in the level of its code. The log has no caller, because it is not registered by
+// the application.
+func (c Config) log(service logs.Service, call call) {
+	code := status.Code(call.err)
+	fields := []logs.Field{
+		logs.Any("grpc.kind", call.kind),
+		logs.Any("grpc.method", call.method),
+		logs.Any("grpc.peer", call.peer),
+		logs.Any("grpc.code", code.String()),
+		logs.Any("grpc.duration", call.duration),
+		logs.Any("grpc.sent_size", call.sentSize),
+		logs.Any("grpc.received_size", call.receivedSize),
+	}
+	if call.err != nil {
+		fields = append(fields, logs.Err(call.err))
+	}
+	level := c.Level(code)
+	switch level {
+	case logs.LevelTrace, logs.LevelDebug, logs.LevelInfo, logs.LevelNotice, logs.LevelWarning, logs.LevelError:
+	case logs.LevelPanic, logs.LevelFatal:
+		level = logs.LevelError
+	default:
+		level = logs.LevelInfo
+	}
+	service.Log(level, call.method+" "+code.String(), fields...)
+}
+
+// serverStream is a grpc.ServerStream with the context of the handler, that counts the sizes of the messages.
+type serverStream struct {
+	grpc.ServerStream
+	ctx          context.Context
+	sentSize     int
+	receivedSize int
+}
+
+// Context returns the context of the handler, with the child service.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// SendMsg sends the message and counts its size.
+func (s *serverStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sentSize += messageSize(m)
+	}
+	return err
+}
+
+// RecvMsg receives a message and counts its size.
+func (s *serverStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.receivedSize += messageSize(m)
+	}
+	return err
+}
+
+// clientStream is a grpc.ClientStream that counts the sizes of the messages and registers the log when it ends.
+type clientStream struct {
+	grpc.ClientStream
+	config  Config
+	service logs.Service
+	desc    *grpc.StreamDesc
+	call    call
+	start   time.Time
+	mu      sync.Mutex
+	once    sync.Once
+}
+
+// SendMsg sends the message and counts its size.
+func (s *clientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.mu.Lock()
+		s.call.sentSize += messageSize(m)
+		s.mu.Unlock()
+	}
+	return err
+}
+
+// RecvMsg receives a message and counts its size. The log is registered when the stream ends.
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		s.mu.Lock()
+		s.call.receivedSize += messageSize(m)
+		s.mu.Unlock()
+		if !s.desc.ServerStreams {
+			s.finish(nil)
+		}
+	case errors.Is(err, io.EOF):
+		s.finish(nil)
+	default:
+		s.finish(err)
+	}
+	return err
+}
+
+// finish registers the log of the stream once.
+func (s *clientStream) finish(err error) {
+	s.once.Do(func() {
+		s.mu.Lock()
+		call := s.call
+		s.mu.Unlock()
+		call.err = err
+		call.duration = time.Since(s.start)
+		s.config.log(s.service, call)
+	})
+}
+
+// messageSize returns the size of the protobuf message. It is 0 for the values that are not protobuf messages.
+func messageSize(m any) int {
+	if message, ok := m.(proto.Message); ok {
+		return proto.Size(message)
+	}
+	return 0
+}
+
+// peerAddress returns the address of the peer of the context.
+func peerAddress(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// firstValue returns the first value of the metadata key, or an empty string.
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,201 @@
+package grpclogs
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fsandov/logs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// recordSink is a sink that keeps the entries it receives.
+type recordSink struct {
+	mu      sync.Mutex
+	entries []logs.Entry
+}
+
+func (r *recordSink) Write(entry logs.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+// fields returns the fields of the entries of the kind provided.
+func (r *recordSink) fields(kind string) []map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var fields []map[string]any
+	for _, entry := range r.entries {
+		values := map[string]any{"level": entry.Level, "file": entry.File}
+		for _, field := range entry.Fields {
+			values[field.Key] = field.Value
+		}
+		if values["grpc.kind"] == kind {
+			fields = append(fields, values)
+		}
+	}
+	return fields
+}
+
+// newClient starts an in-process server with the health service and the interceptors, and returns a client for it.
+func newClient(t *testing.T, sink *recordSink) healthpb.HealthClient {
+	service := logs.NewService(logs.Service{Sinks: []logs.Sink{sink}})
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(service, Config{})),
+		grpc.StreamInterceptor(StreamServerInterceptor(service, Config{})),
+	)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("users", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(service, Config{})),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(service, Config{})),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	// requestSize is the size of the HealthCheckRequest of the tests.
+	const requestSize = 7
+	type want struct {
+		code         string
+		level        logs.Level
+		responseSize int
+	}
+	tests := []struct {
+		name    string
+		service string
+		want    want
+	}{
+		{
+			name:    "Unary interceptors with a successful call",
+			service: "users",
+			want:    want{code: "OK", level: logs.LevelInfo, responseSize: 2},
+		},
+		{
+			name:    "Unary interceptors with an error",
+			service: "other",
+			want:    want{code: "NotFound", level: logs.LevelInfo},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordSink{}
+			client := newClient(t, sink)
+			ctx := logs.ContextWithRequestID(context.Background(), "req-1")
+			var header metadata.MD
+
+			_, _ = client.Check(ctx, &healthpb.HealthCheckRequest{Service: tt.service}, grpc.Header(&header))
+
+			assert.Equal(t, []string{"req-1"}, header.Get(RequestIDKey))
+			for _, kind := range []string{"server", "client"} {
+				fields := sink.fields(kind)
+				require.Len(t, fields, 1)
+				assert.Equal(t, tt.want.level, fields[0]["level"])
+				assert.Equal(t, "/grpc.health.v1.Health/Check", fields[0]["grpc.method"])
+				assert.Equal(t, tt.want.code, fields[0]["grpc.code"])
+				assert.Equal(t, "req-1", fields[0][logs.RequestIDField])
+				assert.Empty(t, fields[0]["file"])
+				assert.Equal(t, requestSize+tt.want.responseSize, fields[0]["grpc.sent_size"].(int)+fields[0]["grpc.received_size"].(int))
+			}
+			assert.Equal(t, "bufconn", sink.fields("server")[0]["grpc.peer"])
+			assert.Equal(t, "passthrough:///bufnet", sink.fields("client")[0]["grpc.peer"])
+		})
+	}
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	sink := &recordSink{}
+	client := newClient(t, sink)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "users"})
+	require.NoError(t, err)
+	response, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+	cancel()
+	_, err = stream.Recv()
+	assert.Error(t, err)
+
+	clientFields := sink.fields("client")
+	require.Len(t, clientFields, 1)
+	assert.Equal(t, codes.Canceled.String(), clientFields[0]["grpc.code"])
+	assert.Equal(t, "/grpc.health.v1.Health/Watch", clientFields[0]["grpc.method"])
+	assert.Equal(t, 2, clientFields[0]["grpc.received_size"])
+	assert.Eventually(t, func() bool { return len(sink.fields("server")) == 1 }, time.Second, 10*time.Millisecond)
+	server := sink.fields("server")[0]
+	assert.Equal(t, "/grpc.health.v1.Health/Watch", server["grpc.method"])
+	assert.Equal(t, 2, server["grpc.sent_size"])
+	assert.Len(t, server[logs.RequestIDField], 32)
+}
+
+func TestUnaryServerInterceptor_requestID(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		want      string
+	}{
+		{name: "Request ID of the metadata", requestID: "req-1", want: "req-1"},
+		{name: "Request ID with spaces", requestID: "req 1"},
+		{name: "Request ID too long", requestID: strings.Repeat("a", 129)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordSink{}
+			client := newClient(t, sink)
+			ctx := metadata.AppendToOutgoingContext(context.Background(), RequestIDKey, tt.requestID)
+
+			_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "users"})
+			require.NoError(t, err)
+
+			fields := sink.fields("server")
+			require.Len(t, fields, 1)
+			if tt.want != "" {
+				assert.Equal(t, tt.want, fields[0][logs.RequestIDField])
+				return
+			}
+			assert.Len(t, fields[0][logs.RequestIDField], 32)
+		})
+	}
+}
+
+func TestDefaultLevel(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want logs.Level
+	}{
+		{code: codes.OK, want: logs.LevelInfo},
+		{code: codes.NotFound, want: logs.LevelInfo},
+		{code: codes.Unavailable, want: logs.LevelWarning},
+		{code: codes.DeadlineExceeded, want: logs.LevelWarning},
+		{code: codes.Internal, want: logs.LevelError},
+		{code: codes.Unknown, want: logs.LevelError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, DefaultLevel(tt.code))
+		})
+	}
+}
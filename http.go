@@ -0,0 +1,168 @@
+package logs
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// RequestIDHeader is the default header of the request ID.
+	RequestIDHeader = "X-Request-ID"
+	// requestIDMaxSize is the maximum size of a request ID received. Longer IDs are replaced.
+	requestIDMaxSize = 128
+)
+
+// HTTPConfig is the struct that contains the configuration of the Middleware. All the fields are optional.
+type HTTPConfig struct {
+	// TrustedProxyHeaders are the headers set by the trusted proxies with the IP of the client, in order of priority.
+	// Example: X-Forwarded-For, X-Real-IP. The first IP of the header is used. If it is not provided, or the headers
+	// are not in the request, the IP of the connection is used.
+	TrustedProxyHeaders []string
+	// RequestIDHeader is the header where the request ID is received and returned. If it is not provided, it will be
+	// RequestIDHeader. When the request has no ID, a new one is generated.
+	RequestIDHeader string
+}
+
+// Middleware returns a net/http middleware that registers one log for every request, with the method, path, route
+// pattern, status, bytes written, duration, IP of the client and user agent. The level is Error for the 5xx status,
+// Warning for the 4xx status and Info for the rest. The handlers receive in the context of the request a child of
+// the service with the request ID, that is returned by FromContext.
+func (s Service) Middleware(config HTTPConfig) func(http.Handler) http.Handler {
+	if config.RequestIDHeader == "" {
+		config.RequestIDHeader = RequestIDHeader
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			requestID := RequestIDOrNew(r.Header.Get(config.RequestIDHeader))
+			w.Header().Set(config.RequestIDHeader, requestID)
+			ctx := ContextWithRequestID(r.Context(), requestID)
+			if traceparent := r.Header.Get("traceparent"); traceparent != "" {
+				ctx = ContextWithTraceparent(ctx, traceparent)
+			}
+			service := s.WithContext(ctx)
+			r = r.WithContext(NewContext(ctx, service))
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(recorder, r)
+
+			service.accessLog(r, recorder, time.Since(start), remoteIP(r, config.TrustedProxyHeaders))
+		})
+	}
+}
+
+// accessLog registers the log of the request. The log has no caller, because it is not registered by the application.
+func (s Service) accessLog(r *http.Request, recorder *statusRecorder, duration time.Duration, ip string) {
+	level := LevelInfo
+	switch {
+	case recorder.status >= http.StatusInternalServerError:
+		level = LevelError
+	case recorder.status >= http.StatusBadRequest:
+		level = LevelWarning
+	}
+	service := s.With(
+		Any("method", r.Method),
+		Any("path", r.URL.Path),
+		Any("route", r.Pattern),
+		Any("status", recorder.status),
+		Any("bytes", recorder.bytes),
+		Any("duration", duration),
+		Any("remote_ip", ip),
+		Any("user_agent", r.UserAgent()),
+	)
+	service.registerOrchestrator(service.internalEntry(level, fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, recorder.status)))
+}
+
+// statusRecorder is a http.ResponseWriter that records the status and the bytes written of the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+// WriteHeader records the status and sends it.
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the bytes written and sends them.
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Flush sends the buffered data to the client, when the http.ResponseWriter supports it.
+func (r *statusRecorder) Flush() {
+	_ = http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// Hijack takes over the connection, when the http.ResponseWriter supports it. It is used by the upgraders of
+// WebSocket. When the status was not written, it is recorded as 101 Switching Protocols.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil && !r.wroteHeader {
+		r.status = http.StatusSwitchingProtocols
+		r.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the original http.ResponseWriter. It is used by http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// remoteIP returns the IP of the client. It is the first valid IP of the trusted proxy headers,
+// or the IP of the connection.
+func remoteIP(r *http.Request, trustedProxyHeaders []string) string {
+	for _, header := range trustedProxyHeaders {
+		value := r.Header.Get(header)
+		if value == "" {
+			continue
+		}
+		ip := strings.TrimSpace(strings.Split(value, ",")[0])
+		if net.ParseIP(ip) != nil {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// RequestIDOrNew returns the request ID received when it is valid, or a new random request ID of 16 bytes in hex.
+// A valid request ID has at most 128 characters, and only printable ASCII characters without spaces.
+// It is used by the Middleware and by the packages that receive the request ID, like the interceptors of grpclogs.
+func RequestIDOrNew(received string) string {
+	if received == "" || len(received) > requestIDMaxSize || strings.ContainsFunc(received, invalidRequestIDRune) {
+		return newRequestID()
+	}
+	return received
+}
+
+// newRequestID returns a new random request ID of 16 bytes in hex.
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// invalidRequestIDRune returns true for the characters not accepted in a request ID received.
+func invalidRequestIDRune(r rune) bool {
+	return r < 0x21 || r > 0x7e
+}
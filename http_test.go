@@ -0,0 +1,188 @@
+package logs
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Middleware(t *testing.T) {
+	type args struct {
+		target  string
+		headers map[string]string
+	}
+	type want struct {
+		level     Level
+		status    int
+		route     string
+		remoteIP  string
+		requestID string
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "Middleware with a successful request",
+			args: args{target: "/users/7", headers: map[string]string{RequestIDHeader: "req-1"}},
+			want: want{level: LevelInfo, status: http.StatusOK, route: "GET /users/{id}", remoteIP: "192.0.2.1", requestID: "req-1"},
+		},
+		{
+			name: "Middleware with a client error",
+			args: args{target: "/missing", headers: map[string]string{"X-Forwarded-For": "203.0.113.9, 10.0.0.1"}},
+			want: want{level: LevelWarning, status: http.StatusNotFound, remoteIP: "203.0.113.9"},
+		},
+		{
+			name: "Middleware with a server error",
+			args: args{target: "/fail", headers: map[string]string{"X-Forwarded-For": "invalid", "X-Real-IP": "203.0.113.10"}},
+			want: want{level: LevelError, status: http.StatusInternalServerError, route: "/fail", remoteIP: "203.0.113.10"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordSink{}
+			logService := NewService(Service{Sinks: []Sink{sink}})
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+				FromContext(r.Context()).Info("handler")
+				_, _ = w.Write([]byte("user"))
+			})
+			mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "fail", http.StatusInternalServerError)
+			})
+			handler := logService.Middleware(HTTPConfig{TrustedProxyHeaders: []string{"X-Forwarded-For", "X-Real-IP"}})(mux)
+			request := httptest.NewRequest(http.MethodGet, tt.args.target, nil)
+			request.Header.Set("User-Agent", "test")
+			for key, value := range tt.args.headers {
+				request.Header.Set(key, value)
+			}
+			response := httptest.NewRecorder()
+
+			handler.ServeHTTP(response, request)
+
+			entry := sink.entries[len(sink.entries)-1]
+			fields := fieldsMap(entry.Fields)
+			requestID := response.Header().Get(RequestIDHeader)
+			assert.Equal(t, tt.want.level, entry.Level)
+			assert.Equal(t, tt.want.status, fields["status"])
+			assert.Equal(t, tt.want.route, fields["route"])
+			assert.Equal(t, tt.want.remoteIP, fields["remote_ip"])
+			assert.Equal(t, "test", fields["user_agent"])
+			assert.Equal(t, response.Body.Len(), fields["bytes"])
+			assert.Equal(t, requestID, fields[RequestIDField])
+			assert.Empty(t, entry.File)
+			if tt.want.requestID != "" {
+				assert.Equal(t, tt.want.requestID, requestID)
+				assert.Equal(t, requestID, fieldsMap(sink.entries[0].Fields)[RequestIDField])
+			} else {
+				assert.Len(t, requestID, 32)
+			}
+		})
+	}
+}
+
+func Test_remoteIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		trusted []string
+		want    string
+	}{
+		{
+			name: "remoteIP without trusted headers",
+			headers: map[string]string{
+				"X-Forwarded-For": "203.0.113.9",
+			},
+			want: "192.0.2.1",
+		},
+		{
+			name:    "remoteIP with a trusted header",
+			headers: map[string]string{"X-Forwarded-For": "203.0.113.9, 10.0.0.1"},
+			trusted: []string{"X-Forwarded-For"},
+			want:    "203.0.113.9",
+		},
+		{
+			name:    "remoteIP with an IPv6 address",
+			headers: map[string]string{"X-Real-IP": "2001:db8::1"},
+			trusted: []string{"X-Forwarded-For", "X-Real-IP"},
+			want:    "2001:db8::1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, value := range tt.headers {
+				request.Header.Set(key, value)
+			}
+			assert.Equal(t, tt.want, remoteIP(request, tt.trusted))
+		})
+	}
+}
+
+func TestRequestIDOrNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		received string
+		want     string
+	}{
+		{name: "RequestIDOrNew with a valid request ID", received: "req-1", want: "req-1"},
+		{name: "RequestIDOrNew without a request ID"},
+		{name: "RequestIDOrNew with spaces", received: "req 1"},
+		{name: "RequestIDOrNew with a line break", received: "req\n1"},
+		{name: "RequestIDOrNew with a request ID too long", received: strings.Repeat("a", 129)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestID := RequestIDOrNew(tt.received)
+
+			if tt.want != "" {
+				assert.Equal(t, tt.want, requestID)
+				return
+			}
+			assert.Len(t, requestID, 32)
+		})
+	}
+}
+
+func TestService_Middleware_hijack(t *testing.T) {
+	sink := &recordSink{}
+	logService := NewService(Service{Sinks: []Sink{sink}})
+	server := httptest.NewServer(logService.Middleware(HTTPConfig{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			http.Error(w, "hijack not supported", http.StatusInternalServerError)
+			return
+		}
+		conn, rw, err := hijacker.Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		_ = rw.Flush()
+	})))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("GET /ws HTTP/1.1\r\nHost: example.com\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n"))
+	require.NoError(t, err)
+	response, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+	assert.Eventually(t, func() bool {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		return len(sink.entries) == 1 && fieldsMap(sink.entries[0].Fields)["status"] == http.StatusSwitchingProtocols
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,297 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LoggerField is the field where the name of a child service created with Named is attached to the logs.
+const LoggerField = "logger"
+
+// levelRegistry keeps the minimum levels of a service and of its named children. It is shared by all the copies of
+// the service, so a change of a level reaches all of them. The levels are read without locks.
+type levelRegistry struct {
+	// levels are the minimum levels by name. The level of the service is saved with an empty name.
+	levels atomic.Pointer[map[string]Level]
+	mu     sync.Mutex
+	// names are the names of the children created with Named.
+	names map[string]struct{}
+	// reverts are the changes with a TTL that are pending to be reverted, by name.
+	reverts map[string]levelRevert
+	version uint64
+}
+
+// levelRevert is a change of a level with a TTL that is pending to be reverted.
+type levelRevert struct {
+	// version identifies the last change with a TTL of the name.
+	version uint64
+	// previous is the level of the name before the first of its pending changes with a TTL.
+	previous Level
+}
+
+// levelState is the body of the responses of the LevelHandler, and of the requests to change a level.
+type levelState struct {
+	// Logger is the name of a child created with Named. It is empty for the service.
+	Logger string `json:"logger,omitempty"`
+	// Level is the minimum level.
+	Level Level `json:"level"`
+	// TTL is the time after which a change is reverted. Example: 10m
+	TTL string `json:"ttl,omitempty"`
+	// Loggers are the minimum levels of the children created with Named.
+	Loggers map[string]Level `json:"loggers,omitempty"`
+}
+
+// newLevelRegistry returns a registry with the minimum level of the service provided.
+func newLevelRegistry(level Level) *levelRegistry {
+	registry := &levelRegistry{names: map[string]struct{}{}, reverts: map[string]levelRevert{}}
+	registry.levels.Store(&map[string]Level{"": level})
+	return registry
+}
+
+// level returns the minimum level of the name. The names without their own level take the level of their parent,
+// which is the name without its last part. Example: api.db takes the level of api.
+func (r *levelRegistry) level(name string) Level {
+	levels := *r.levels.Load()
+	for {
+		if level, ok := levels[name]; ok {
+			return level
+		}
+		if name == "" {
+			return LevelTrace
+		}
+		index := strings.LastIndex(name, ".")
+		if index < 0 {
+			name = ""
+		} else {
+			name = name[:index]
+		}
+	}
+}
+
+// set changes the minimum level of the name. An empty level removes the level of a child, so it takes the level
+// of its parent again. It returns the previous level of the name, which is empty when it had none.
+func (r *levelRegistry) set(name string, level Level) Level {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.reverts, name)
+	return r.store(name, level)
+}
+
+// setTTL changes the minimum level of the name like set, and reverts the change after the TTL, unless the level of
+// the name is changed again before. When a change with a TTL replaces another one that is pending, the level before
+// the first change is the one restored.
+func (r *levelRegistry) setTTL(name string, level Level, ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	previous := r.store(name, level)
+	if pending, ok := r.reverts[name]; ok {
+		previous = pending.previous
+	}
+	r.version++
+	version := r.version
+	r.reverts[name] = levelRevert{version: version, previous: previous}
+	time.AfterFunc(ttl, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.reverts[name].version != version {
+			return
+		}
+		delete(r.reverts, name)
+		r.store(name, previous)
+	})
+}
+
+// store saves a copy of the levels with the level of the name changed. It must be called with the lock.
+func (r *levelRegistry) store(name string, level Level) Level {
+	current := *r.levels.Load()
+	levels := make(map[string]Level, len(current)+1)
+	for key, value := range current {
+		levels[key] = value
+	}
+	previous := levels[name]
+	if level == "" && name != "" {
+		delete(levels, name)
+	} else if level != "" {
+		levels[name] = level
+	}
+	r.levels.Store(&levels)
+	return previous
+}
+
+// state returns the minimum levels of the service and of its named children.
+func (r *levelRegistry) state() levelState {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.names))
+	for name := range r.names {
+		names = append(names, name)
+	}
+	r.mu.Unlock()
+	sort.Strings(names)
+	state := levelState{Level: r.level("")}
+	for _, name := range names {
+		if state.Loggers == nil {
+			state.Loggers = map[string]Level{}
+		}
+		state.Loggers[name] = r.level(name)
+	}
+	return state
+}
+
+// ParseLevel returns the level of the name provided, without case sensitivity. It accepts the names of the levels
+// and the short names WARN and ERR. Example: debug
+func ParseLevel(name string) (Level, error) {
+	level, ok := levelNames[strings.ToUpper(strings.TrimSpace(name))]
+	if !ok {
+		return "", fmt.Errorf("logs: unknown level %q", name)
+	}
+	return level, nil
+}
+
+// Named returns a child of the service with the name provided, that is attached to its logs in the LoggerField.
+// The name of a child of a named service is joined with a dot, and it replaces the name of the parent. Example: api.db
+// The child has the minimum level of the service until its own level is changed with SetLevel or the LevelHandler.
+func (s Service) Named(name string) Service {
+	if s.name != "" {
+		name = s.name + "." + name
+	}
+	if s.levels != nil {
+		s.levels.mu.Lock()
+		s.levels.names[name] = struct{}{}
+		s.levels.mu.Unlock()
+	}
+	s.name = name
+	fields := make([]Field, 0, len(s.fields)+1)
+	for _, field := range s.fields {
+		if field.Key != LoggerField {
+			fields = append(fields, field)
+		}
+	}
+	s.fields = append(fields, Any(LoggerField, name))
+	return s
+}
+
+// Enabled reports whether the service registers the logs of the level provided. The logs below the minimum level
+// of the service are dropped. The services that were not created with NewService register all the logs.
+func (s Service) Enabled(level Level) bool {
+	if s.levels == nil {
+		return true
+	}
+	return level.severity() >= s.levels.level(s.name).severity()
+}
+
+// CurrentLevel returns the minimum level of the service.
+func (s Service) CurrentLevel() Level {
+	if s.levels == nil {
+		return LevelTrace
+	}
+	return s.levels.level(s.name)
+}
+
+// SetLevel changes the minimum level of the service, and of all its copies, atomically. For a child created with
+// Named, an empty level removes its own level, so it takes the level of its parent again.
+// It does nothing in the services that were not created with NewService.
+func (s Service) SetLevel(level Level) {
+	if s.levels != nil {
+		s.levels.set(s.name, level)
+	}
+}
+
+// LevelHandler returns a http.Handler to see and to change the minimum levels of the service and of its named children.
+// GET returns the levels as JSON. Example: {"level":"INFO","loggers":{"api":"DEBUG"}}
+// PUT changes a level with a JSON body. The logger is optional and the TTL reverts the change after the time provided.
+// Example: {"logger":"api","level":"DEBUG","ttl":"10m"}
+func (s Service) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.levels == nil {
+			levelResponse(w, http.StatusNotImplemented, map[string]string{"error": "the service was not created with NewService"})
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			levelResponse(w, http.StatusOK, s.levels.state())
+		case http.MethodPut:
+			var request levelState
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				levelResponse(w, http.StatusBadRequest, map[string]string{"error": "invalid body: " + err.Error()})
+				return
+			}
+			level, err := ParseLevel(string(request.Level))
+			if err != nil && !(request.Level == "" && request.Logger != "") {
+				levelResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			if request.TTL == "" {
+				s.levels.set(request.Logger, level)
+				levelResponse(w, http.StatusOK, s.levels.state())
+				return
+			}
+			ttl, err := time.ParseDuration(request.TTL)
+			if err != nil || ttl <= 0 {
+				levelResponse(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid ttl %q", request.TTL)})
+				return
+			}
+			s.levels.setTTL(request.Logger, level, ttl)
+			levelResponse(w, http.StatusOK, s.levels.state())
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			levelResponse(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		}
+	})
+}
+
+// levelResponse writes the value as the JSON body of the response, with the status provided.
+func levelResponse(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// LevelSinkConfig is the struct that contains the configuration of the LevelSink. The Sink is required.
+type LevelSinkConfig struct {
+	// Sink is the sink that receives the entries.
+	Sink Sink
+	// MinLevel is the minimum level of the entries written to the Sink. If it is not provided, all the entries are written.
+	MinLevel Level
+}
+
+// LevelSink is a sink that writes to another sink only the entries from a minimum level. It is used to give a sink
+// a level different from the level of the service. Example: only the errors are sent to the webhook.
+type LevelSink struct {
+	config LevelSinkConfig
+}
+
+// NewLevelSink returns a new LevelSink with the configuration provided.
+func NewLevelSink(config LevelSinkConfig) *LevelSink {
+	return &LevelSink{config: config}
+}
+
+// Write writes the entry to the sink when its level is equal or above the MinLevel.
+func (l *LevelSink) Write(entry Entry) error {
+	if l.config.MinLevel != "" && entry.Level.severity() < l.config.MinLevel.severity() {
+		return nil
+	}
+	return l.config.Sink.Write(entry)
+}
+
+// Flush flushes the sink when it implements Flusher.
+func (l *LevelSink) Flush() error {
+	if flusher, ok := l.config.Sink.(Flusher); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// Close closes the sink when it implements io.Closer.
+func (l *LevelSink) Close() error {
+	if closer, ok := l.config.Sink.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
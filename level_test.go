@@ -0,0 +1,220 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Enabled(t *testing.T) {
+	type want struct {
+		enabled bool
+	}
+	tests := []struct {
+		name     string
+		minLevel Level
+		level    Level
+		want     want
+	}{
+		{
+			name:  "Enabled without MinLevel",
+			level: LevelTrace,
+			want:  want{enabled: true},
+		},
+		{
+			name:     "Enabled when the level is the MinLevel",
+			minLevel: LevelWarning,
+			level:    LevelWarning,
+			want:     want{enabled: true},
+		},
+		{
+			name:     "Enabled when the level is below the MinLevel",
+			minLevel: LevelWarning,
+			level:    LevelInfo,
+			want:     want{enabled: false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logService := NewService(Service{MinLevel: tt.minLevel})
+
+			assert.Equal(t, tt.want.enabled, logService.Enabled(tt.level))
+		})
+	}
+}
+
+func TestHandler_Enabled(t *testing.T) {
+	handler := NewHandler(NewService(Service{MinLevel: LevelWarning}))
+
+	assert.False(t, handler.Enabled(context.Background(), slog.LevelInfo))
+	assert.True(t, handler.Enabled(context.Background(), slog.LevelWarn))
+	assert.True(t, NewHandler(Service{}).Enabled(context.Background(), SlogLevelTrace))
+}
+
+func TestService_Named(t *testing.T) {
+	sink := &recordSink{}
+	logService := NewService(Service{MinLevel: LevelInfo, Sinks: []Sink{sink}})
+	api := logService.Named("api")
+	db := api.Named("db")
+
+	db.SetLevel(LevelDebug)
+	db.Debug("query")
+	api.Debug("request")
+	logService.With(Any("user", "fsandov")).SetLevel(LevelError)
+	api.Warning("slow")
+	db.Debug("query")
+
+	assert.Len(t, sink.entries, 2)
+	assert.Equal(t, []Field{Any(LoggerField, "api.db")}, sink.entries[0].Fields)
+	assert.Equal(t, LevelError, api.CurrentLevel())
+	db.SetLevel("")
+	assert.Equal(t, LevelError, db.CurrentLevel())
+}
+
+func TestService_LevelHandler(t *testing.T) {
+	type args struct {
+		method string
+		body   string
+	}
+	type want struct {
+		status int
+		body   string
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "LevelHandler with GET",
+			args: args{method: http.MethodGet},
+			want: want{status: http.StatusOK, body: `{"level":"INFO","loggers":{"api":"INFO","api.db":"INFO"}}`},
+		},
+		{
+			name: "LevelHandler with PUT",
+			args: args{method: http.MethodPut, body: `{"level":"debug"}`},
+			want: want{status: http.StatusOK, body: `{"level":"DEBUG","loggers":{"api":"DEBUG","api.db":"DEBUG"}}`},
+		},
+		{
+			name: "LevelHandler with PUT of a logger",
+			args: args{method: http.MethodPut, body: `{"logger":"api","level":"WARN"}`},
+			want: want{status: http.StatusOK, body: `{"level":"INFO","loggers":{"api":"WARNING","api.db":"WARNING"}}`},
+		},
+		{
+			name: "LevelHandler with PUT of an unknown level",
+			args: args{method: http.MethodPut, body: `{"level":"verbose"}`},
+			want: want{status: http.StatusBadRequest, body: `{"error":"logs: unknown level \"verbose\""}`},
+		},
+		{
+			name: "LevelHandler with PUT of an invalid TTL",
+			args: args{method: http.MethodPut, body: `{"level":"DEBUG","ttl":"soon"}`},
+			want: want{status: http.StatusBadRequest, body: `{"error":"invalid ttl \"soon\""}`},
+		},
+		{
+			name: "LevelHandler with POST",
+			args: args{method: http.MethodPost},
+			want: want{status: http.StatusMethodNotAllowed, body: `{"error":"method not allowed"}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logService := NewService(Service{MinLevel: LevelInfo})
+			logService.Named("api").Named("db")
+			response := httptest.NewRecorder()
+
+			logService.LevelHandler().ServeHTTP(response, httptest.NewRequest(tt.args.method, "/level", strings.NewReader(tt.args.body)))
+
+			assert.Equal(t, tt.want.status, response.Code)
+			assert.JSONEq(t, tt.want.body, response.Body.String())
+		})
+	}
+}
+
+func TestService_LevelHandlerTTL(t *testing.T) {
+	logService := NewService(Service{MinLevel: LevelInfo})
+	api := logService.Named("api")
+	handler := logService.LevelHandler()
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/level", strings.NewReader(`{"logger":"api","level":"TRACE","ttl":"20ms"}`)))
+	assert.Equal(t, LevelTrace, api.CurrentLevel())
+	assert.Eventually(t, func() bool { return api.CurrentLevel() == LevelInfo }, time.Second, 5*time.Millisecond)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/level", strings.NewReader(`{"level":"ERROR","ttl":"20ms"}`)))
+	logService.SetLevel(LevelWarning)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, LevelWarning, logService.CurrentLevel())
+}
+
+func TestService_LevelHandlerTTL_overlapping(t *testing.T) {
+	logService := NewService(Service{MinLevel: LevelInfo})
+	handler := logService.LevelHandler()
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/level", strings.NewReader(`{"level":"DEBUG","ttl":"300ms"}`)))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/level", strings.NewReader(`{"level":"TRACE","ttl":"100ms"}`)))
+	assert.Equal(t, LevelTrace, logService.CurrentLevel())
+	assert.Eventually(t, func() bool { return logService.CurrentLevel() == LevelInfo }, time.Second, 5*time.Millisecond)
+
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, LevelInfo, logService.CurrentLevel())
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Level
+		wantErr bool
+	}{
+		{name: "debug", want: LevelDebug},
+		{name: " Warn ", want: LevelWarning},
+		{name: "PANIC", want: LevelPanic},
+		{name: "verbose", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, err := ParseLevel(tt.name)
+
+			assert.Equal(t, tt.want, level)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestLevelSink_Write(t *testing.T) {
+	sink := &flushSink{}
+	levelSink := NewLevelSink(LevelSinkConfig{Sink: sink, MinLevel: LevelError})
+
+	assert.NoError(t, levelSink.Write(Entry{Level: LevelWarning}))
+	assert.NoError(t, levelSink.Write(Entry{Level: LevelFatal}))
+	assert.NoError(t, levelSink.Flush())
+	assert.Equal(t, []Entry{{Level: LevelFatal}}, sink.entries)
+	assert.Equal(t, 1, sink.flushed)
+}
+
+// countStringer counts the calls of String.
+type countStringer struct {
+	calls *int
+}
+
+func (c countStringer) String() string {
+	*c.calls++
+	return "value"
+}
+
+func TestService_Enabled_skipsDisabledLogs(t *testing.T) {
+	sink := &recordSink{}
+	logService := NewService(Service{MinLevel: LevelInfo, StackLevel: LevelTrace, Sinks: []Sink{sink}})
+	calls := 0
+
+	logService.Debugf("value %s", countStringer{calls: &calls})
+	allocs := testing.AllocsPerRun(100, func() { logService.Debug("message") })
+
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, float64(0), allocs)
+	assert.Empty(t, sink.entries)
+}
@@ -3,11 +3,12 @@ package logs
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"runtime"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -22,158 +23,483 @@ type Service struct {
 	// The URL could be a Discord webhook URL. Example: https://discordapp.com/api/webhooks/1234567890/abcdefghijklmnopqrstuvwxyz
 	// The logs will be sent as a Discord message.
 	URL string
+	// URLDedup is the window in which the identical logs sent to the URL are collapsed, as in the DedupSink.
+	// If it is not provided, all the logs are sent to the URL. When it is provided, Close should be called when the
+	// service is not used anymore.
+	URLDedup time.Duration
+	// URLRateLimit is the limit of the logs sent to the URL, as in the RateLimitSink. The Sink of the configuration is
+	// not used. If it is not provided, all the logs are sent to the URL. When it is provided, Close should be called
+	// when the service is not used anymore.
+	URLRateLimit *RateLimitConfig
+	// webhook is the sink of the URL when the logs sent to the URL are deduplicated or rate limited.
+	webhook Sink
 	// FileLog is a boolean that indicates if the logs should be saved in a file. If it is true, the logs will be saved in a file.
 	// The file will be created in the same folder where the application is running. The name of the file will be the name of the application.
 	// If the name of the application is not provided, the name of the file will be "logs".
 	FileLog bool
+	// Dir is the folder where the file of the logs is saved. It is created if it does not exist.
+	// If it is not provided, it will be "logs", in the same folder where the application is running.
+	Dir string
 	// fileName is the name of the file where the logs will be saved. It is used internally.
 	fileName string
 	// ShowDate is a boolean that indicates if the date should be shown in the logs. If it is true, the date will be shown in the logs.
 	ShowDate bool
 	// ShowTime is a boolean that indicates if the time should be shown in the logs. If it is true, the time will be shown in the logs.
 	ShowTime bool
+	// Sinks are the extra destinations of the logs, like the SplunkSink. Every entry is written to all the sinks.
+	Sinks []Sink
+	// CallerFormat is the format of the caller in the logs. If it is not provided, it will be CallerFileName.
+	CallerFormat CallerFormat
+	// Format is the format of the logs printed in the console, saved in the file and sent to the URL.
+	// If it is not provided, it will be FormatText. With FormatPretty, only the console is colorized.
+	Format Format
+	// ExitCode is the code the application exits with after a log with the level Fatal. If it is not provided, it will be 1.
+	ExitCode int
+	// ExitFunc is the function called to exit the application after a log with the level Fatal.
+	// If it is not provided, it will be os.Exit. It can be replaced in the tests to check the exit without stopping them.
+	ExitFunc func(code int)
+	// RecoverLevel is the level of the logs of the panics caught by Recover and Go. If it is not provided, it will be LevelError.
+	RecoverLevel Level
+	// RePanic is a boolean that indicates if the panics caught by Recover and Go should panic again after they are logged.
+	// If it is false, the panics are swallowed.
+	RePanic bool
+	// StackLevel is the minimum level of the logs that capture the stack trace of the goroutine. Example: LevelError
+	// If it is not provided, the stack trace is not captured.
+	StackLevel Level
+	// Sampling is the configuration of the sampling of the logs. If it is not provided, all the logs are registered.
+	Sampling *SamplingConfig
+	// sampler keeps the counters of the sampling. It is shared by all the copies of the service.
+	sampler *sampler
+	// Redaction is the configuration of the redaction of the sensitive values of the logs, before they reach the console,
+	// the file, the URL and the sinks. If it is not provided, the logs are registered as they are.
+	Redaction *RedactionConfig
+	// redactor redacts the logs with the Redaction.
+	redactor *redactor
+	// MinLevel is the minimum level of the logs registered. The logs below it are dropped. It can be changed while the
+	// application is running with SetLevel or the LevelHandler. If it is not provided, it will be LevelTrace.
+	MinLevel Level
+	// name is the name of the service, set with Named.
+	name string
+	// levels are the minimum levels of the service and of its named children. They are shared by all the copies.
+	levels *levelRegistry
+	// callerSkip is the number of extra frames skipped when looking for the caller. It is added with AddCallerSkip.
+	callerSkip int
+	// fields are the fields attached to all the logs of the service. They are added with With.
+	fields []Field
 }
 
-// NewService returns a new instance of a Service of logs with the configuration provided.
+// Field is a key and value pair attached to a log. It is used to add structured information to the logs.
+type Field struct {
+	// Key is the name of the field.
+	Key string
+	// Value is the value of the field.
+	Value interface{}
+}
+
+// Any returns a Field with the key and the value provided.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Entry is a log registered by the service. It is the value that the sinks receive.
+type Entry struct {
+	// Time is the moment when the log was registered.
+	Time time.Time
+	// Level is the level of the log.
+	Level Level
+	// NameApp is the name of the application that registered the log.
+	NameApp string
+	// Message is the content of the log with its extra information.
+	Message string
+	// Content is the log built as it is printed in the console and saved in the file.
+	Content string
+	// File is the file where the log was registered, in the CallerFormat of the service.
+	File string
+	// Line is the line of the file where the log was registered.
+	Line int
+	// Function is the function where the log was registered, in the CallerFormat of the service.
+	Function string
+	// Fields are the fields attached to the log.
+	Fields []Field
+	// Stack is the stack trace of the goroutine that registered the log. It is captured for the levels from the StackLevel of the service.
+	Stack []Frame
+}
+
+// Sink is a destination of the logs. The service writes every entry to all of its sinks.
+type Sink interface {
+	// Write receives an entry of the log. It returns an error if the entry could not be delivered.
+	Write(entry Entry) error
+}
+
+// Flusher is implemented by the sinks that keep entries pending to be delivered, like the ones that send batches.
+type Flusher interface {
+	// Flush delivers all the pending entries.
+	Flush() error
+}
+
+// fieldsMap returns the fields as a map of keys and values. It returns nil when there are no fields.
+func fieldsMap(fields []Field) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	values := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		values[field.Key] = field.Value
+	}
+	return values
+}
+
+// NewService returns a new instance of a Service of logs with the configuration provided. When the service has
+// URLDedup, URLRateLimit or sinks that send the logs in the background, Close should be called when the application
+// stops.
 func NewService(config Service) Service {
 	if config.NameApp == "" {
 		config.NameApp = "LOGS"
 	}
+	if config.ExitCode == 0 {
+		config.ExitCode = 1
+	}
+	if config.ExitFunc == nil {
+		config.ExitFunc = os.Exit
+	}
+	if config.MinLevel == "" {
+		config.MinLevel = LevelTrace
+	}
+	if config.Dir == "" {
+		config.Dir = pathLogs
+	}
+	var webhook Sink
+	if config.URL != "" && (config.URLDedup > 0 || config.URLRateLimit != nil) {
+		webhook = urlSink{service: Service{URL: config.URL}}
+		if config.URLRateLimit != nil {
+			rateLimit := *config.URLRateLimit
+			rateLimit.Sink = webhook
+			webhook = NewRateLimitSink(rateLimit)
+		}
+		if config.URLDedup > 0 {
+			formatter := Formatter{Format: config.Format, ShowDate: config.ShowDate, ShowTime: config.ShowTime}
+			webhook = NewDedupSink(DedupConfig{Sink: webhook, Window: config.URLDedup, Formatter: formatter})
+		}
+	}
 	var fileName string
 	if config.FileLog {
-		fileName = fmt.Sprintf("%s/%s-%s.log", pathLogs, config.NameApp, time.Now().Format("2006-01-02"))
+		fileName = filepath.Join(config.Dir, fmt.Sprintf("%s-%s.log", config.NameApp, time.Now().Format("2006-01-02")))
 	}
 
 	return Service{
-		NameApp:  config.NameApp,
-		URL:      config.URL,
-		FileLog:  config.FileLog,
-		fileName: fileName,
-		ShowDate: config.ShowDate,
-		ShowTime: config.ShowTime,
+		NameApp:      config.NameApp,
+		URL:          config.URL,
+		URLDedup:     config.URLDedup,
+		URLRateLimit: config.URLRateLimit,
+		webhook:      webhook,
+		FileLog:      config.FileLog,
+		Dir:          config.Dir,
+		fileName:     fileName,
+		ShowDate:     config.ShowDate,
+		ShowTime:     config.ShowTime,
+		Sinks:        config.Sinks,
+		CallerFormat: config.CallerFormat,
+		Format:       config.Format,
+		StackLevel:   config.StackLevel,
+		ExitCode:     config.ExitCode,
+		ExitFunc:     config.ExitFunc,
+		RecoverLevel: config.RecoverLevel,
+		RePanic:      config.RePanic,
+		MinLevel:     config.MinLevel,
+		levels:       newLevelRegistry(config.MinLevel),
+		Sampling:     config.Sampling,
+		sampler:      newSampler(config.Sampling),
+		Redaction:    config.Redaction,
+		redactor:     newRedactor(config.Redaction),
+		callerSkip:   config.callerSkip,
+		fields:       config.fields,
 	}
 }
 
+// With returns a copy of the service that attaches the fields provided to all of its logs.
+// The fields of the service are kept, so With can be called several times.
+func (s Service) With(fields ...Field) Service {
+	s.fields = append(s.fields[:len(s.fields):len(s.fields)], fields...)
+	return s
+}
+
 var (
 	// DefaultService is the default instance of the service.
 	// It is used to call the functions of the service without creating a new instance.
-	// It is initialized with the default configuration.
-	DefaultService = NewService(Service{
-		NameApp:  "LOGS",
-		FileLog:  true,
-		fileName: fmt.Sprintf("%s/%s-%s.log", pathLogs, "LOGS", time.Now().Format("2006-01-02")),
-	})
+	// It is initialized with the default configuration, changed by the environment variables with the prefix LOGS,
+	// like in NewServiceFromEnv. If the variables are not valid, they are ignored and the error is returned by
+	// DefaultServiceError.
+	DefaultService, defaultServiceErr = defaultService()
 )
 
+// Level is the level of a log. It indicates the importance of the log.
+type Level string
+
 const (
-	// logTrace is a log level used for tracing the code and executing steps.
+	// LevelTrace is a log level used for tracing the code and executing steps.
 	// This is the most verbose level. it should not be used in production.
-	logTrace = "TRACE"
-	// logDebug is a log level used for debugging the code.
+	LevelTrace Level = "TRACE"
+	// LevelDebug is a log level used for debugging the code.
 	// It should not be used in production.
-	logDebug = "DEBUG"
-	// logInfo is a log level used for providing information about the execution of the code.
+	LevelDebug Level = "DEBUG"
+	// LevelInfo is a log level used for providing information about the execution of the code.
 	// It should be used in production.
-	logInfo = "INFO"
-	// logNotice is a log level used for all the notable events that are not considered an error.
+	LevelInfo Level = "INFO"
+	// LevelNotice is a log level used for all the notable events that are not considered an error.
 	// It should be used in production.
-	logNotice = "NOTICE"
-	// logWarning is a log level used for all the events that can potentially cause application oddities.
+	LevelNotice Level = "NOTICE"
+	// LevelWarning is a log level used for all the events that can potentially cause application oddities.
 	// It should be used in production.
-	logWarning = "WARNING"
-	// logError is a log level used for all the errors that are not critical and the application can continue running.
+	LevelWarning Level = "WARNING"
+	// LevelError is a log level used for all the errors that are not critical and the application can continue running.
 	// It should be used in production.
-	logError = "ERROR"
-	// logFatal is a log level used for all the errors that are critical and may cause the application to stop running.
+	LevelError Level = "ERROR"
+	// LevelPanic is a log level used for all the errors that break the current execution. The log is registered and then
+	// the function panics, so the panic can be recovered by the caller.
 	// It should be used in production.
-	logFatal      = "FATAL"
-	caller        = "3"
-	callerDefault = "4"
-	pathLogs      = "logs"
+	LevelPanic Level = "PANIC"
+	// LevelFatal is a log level used for all the errors that are critical and stop the application. The log is registered,
+	// the sinks are flushed and then the application exits with the ExitCode of the service.
+	// It should be used in production.
+	LevelFatal Level = "FATAL"
 )
 
-// logBuilder is the function that builds the logs. It is used internally. It receives the content of the log.
-// It returns the content of the log built. This is used to orchestrate the type of logs.
-func (s Service) logBuilder(messageLevel string, callerLevel string, message string, extraMessage ...string) string {
-	switch messageLevel {
-	case logInfo:
-		extraMessageSTR := ""
-		if callerLevel == caller {
-			extraMessageSTR = strings.Join(extraMessage, " ")
-		}
-		logMessage := ""
-		msg := fmt.Sprintf(message + " " + extraMessageSTR)
-		if s.ShowDate {
-			logMessage += fmt.Sprintf("[%s]", time.Now().Format("2006-01-02"))
-		}
-		if s.ShowTime {
-			logMessage += fmt.Sprintf("[%s]", time.Now().Format("15:04:05.999"))
-		}
-		return fmt.Sprintf(
-			"%s[%s]-[%s] %s",
-			logMessage,
-			s.NameApp,
-			messageLevel,
-			msg)
-	default:
-		return s.logDecorator(messageLevel, callerLevel, message, extraMessage...)
+const (
+	pathLogs = "logs"
+)
+
+// levelSeverity is the severity of every level, from the least to the most severe.
+var levelSeverity = map[Level]int{
+	LevelTrace:   0,
+	LevelDebug:   1,
+	LevelInfo:    2,
+	LevelNotice:  3,
+	LevelWarning: 4,
+	LevelError:   5,
+	LevelPanic:   6,
+	LevelFatal:   7,
+}
+
+// severity returns the severity of the level. It is used to compare the levels.
+func (l Level) severity() int {
+	return levelSeverity[l]
+}
+
+// logBuilder is the function that builds the logs. It is used internally. It receives the content of the log,
+// which is used literally and not as a format.
+// It returns the entry of the log built, decorated with its caller.
+func (s Service) logBuilder(messageLevel Level, message string, extraMessage ...string) Entry {
+	text := message + " " + strings.Join(extraMessage, " ")
+	entry := s.logDecorator(Entry{
+		Time:    time.Now(),
+		Level:   messageLevel,
+		NameApp: s.NameApp,
+		Message: strings.TrimSuffix(text, " "),
+		Fields:  s.fields,
+	})
+	entry.Content = s.logContent(entry, text)
+	return entry
+}
+
+// Log is the function that registers a log with the level, message and fields provided, without the caller and the
+// stack trace. It is used by the packages that register logs on behalf of the application, like the interceptors of
+// grpclogs. The levels Panic and Fatal are registered, but the function does not panic or exit.
+// Example: service.Log(logs.LevelInfo, "GET /users 200", logs.Any("duration", duration))
+func (s Service) Log(level Level, message string, fields ...Field) {
+	if !s.Enabled(level) {
+		return
 	}
+	service := s.With(fields...)
+	service.registerOrchestrator(service.internalEntry(level, message))
 }
 
-// logDecorator is the function that decorates the logs. It is used internally. It receives the content of the log.
-// It returns the content of the log decorated.
-func (s Service) logDecorator(messageLevel string, callerLevel string, message string, extraMessage ...string) string {
-	callerLevelINT, err := strconv.Atoi(callerLevel)
-	if err != nil {
-		callerLevelINT, _ = strconv.Atoi(caller)
-	}
-	pc, file, line, _ := runtime.Caller(callerLevelINT)
-	files := strings.Split(file, "/")
-	file = files[len(files)-1]
-	name := runtime.FuncForPC(pc).Name()
-	fns := strings.Split(name, ".")
-	name = fns[len(fns)-1]
-	extraMessageSTR := ""
-	if callerLevel == caller {
-		extraMessageSTR = strings.Join(extraMessage, " ")
+// internalEntry builds the entry of a log registered by this package on behalf of the application, like the access
+// logs. The entry has no caller and no stack trace. It is used internally.
+func (s Service) internalEntry(level Level, message string) Entry {
+	entry := Entry{
+		Time:    time.Now(),
+		Level:   level,
+		NameApp: s.NameApp,
+		Message: message,
+		Fields:  s.fields,
+	}
+	entry.Content = s.logContent(entry, message)
+	return entry
+}
+
+// logDecorator is the function that decorates the logs. It is used internally. It receives the entry of the log.
+// It returns the entry of the log decorated with the file, line and function of the caller.
+func (s Service) logDecorator(entry Entry) Entry {
+	if frame, ok := s.caller(); ok {
+		s.callerDecorator(&entry, frame)
+	}
+	s.stackDecorator(&entry)
+	return entry
+}
+
+// logContent returns the content of the entry as it is printed in the console and saved in the file, in the Format
+// of the service. The logs with the level Info and the logs without caller are not decorated with the caller.
+// The stack trace is printed below the log.
+func (s Service) logContent(entry Entry, text string) string {
+	if s.Format == FormatJSON {
+		return jsonContent(entry)
+	}
+	if len(entry.Stack) > 0 {
+		stack := entry.Stack
+		entry.Stack = nil
+		return s.logContent(entry, text) + "\n" + stackText(stack)
 	}
 	logMessage := ""
-	msg := fmt.Sprintf(message + " " + extraMessageSTR)
 	if s.ShowDate {
-		logMessage += fmt.Sprintf("[%s]", time.Now().Format("2006-01-02"))
+		logMessage += fmt.Sprintf("[%s]", entry.Time.Format("2006-01-02"))
 	}
 	if s.ShowTime {
-		logMessage += fmt.Sprintf("[%s]", time.Now().Format("15:04:05.999"))
+		logMessage += fmt.Sprintf("[%s]", entry.Time.Format("15:04:05.999"))
+	}
+	msg := fieldsMessage(text, entry.Fields)
+	if entry.Level == LevelInfo || entry.Function == "" {
+		return fmt.Sprintf(
+			"%s[%s]-[%s] %s",
+			logMessage,
+			s.NameApp,
+			entry.Level,
+			msg)
+	}
+	if entry.File == "" {
+		return fmt.Sprintf(
+			"%s[%s]-[%s] %s(): %s",
+			logMessage,
+			s.NameApp,
+			entry.Level,
+			entry.Function,
+			msg)
 	}
 	return fmt.Sprintf(
 		"%s[%s]-[%s] %s:%d:%s(): %s",
 		logMessage,
 		s.NameApp,
-		messageLevel,
-		file,
-		line,
-		name,
+		entry.Level,
+		entry.File,
+		entry.Line,
+		entry.Function,
 		msg)
 }
 
+// fieldsMessage returns the message with the fields appended as key=value pairs.
+// The values that contain spaces or quotes are quoted.
+func fieldsMessage(msg string, fields []Field) string {
+	if len(fields) == 0 {
+		return msg
+	}
+	pairs := make([]string, 0, len(fields))
+	for _, field := range fields {
+		pairs = append(pairs, field.Key+"="+fieldValue(field.Value))
+	}
+	return strings.TrimSuffix(msg, " ") + " " + strings.Join(pairs, " ")
+}
+
+// fieldValue returns the value of a field as it is printed in the text logs. The values that are empty or that have
+// spaces, quotes or equal signs are quoted.
+func fieldValue(value interface{}) string {
+	text := fmt.Sprint(value)
+	if text == "" || strings.ContainsAny(text, " \t\n\"=") {
+		return strconv.Quote(text)
+	}
+	return text
+}
+
 // registerOrchestrator is the function that registers the logs in the different services. It is used internally.
-// It is called by the functions of the service. It receives the content of the log.
-func (s Service) registerOrchestrator(content string) {
-	if s.URL != "" {
-		s.postLog(content)
+// It is called by the functions of the service. It receives the entry of the log. The entries below the minimum level
+// are dropped, the entries are sampled when the Sampling is provided, and the sensitive values are redacted when the
+// Redaction is provided.
+func (s Service) registerOrchestrator(entry Entry) {
+	if !s.Enabled(entry.Level) {
+		return
+	}
+	if s.sampler != nil {
+		if report, ok := s.sampler.report(entry.Time, false); ok {
+			s.registerOrchestrator(s.With(report...).internalEntry(LevelWarning, samplingMessage))
+		}
+		if !s.sampler.sample(entry) {
+			metrics.dropped.add(1, entry.NameApp, dropSampled)
+			return
+		}
+	}
+	metrics.records.add(1, entry.NameApp, string(entry.Level))
+	if s.redactor != nil {
+		entry = s.redactor.redact(entry)
+		entry.Content = s.logContent(entry, entry.Message)
+	}
+	if s.webhook != nil {
+		if err := s.webhook.Write(entry); err != nil {
+			fmt.Println(err)
+		}
+	} else if s.URL != "" {
+		s.postLog(discordContent(entry))
 	}
 	if s.FileLog {
-		s.registerFileLog(content)
+		s.registerFileLog(entry)
+	}
+	for _, sink := range s.Sinks {
+		if err := sink.Write(entry); err != nil {
+			fmt.Println(err)
+		}
 	}
 }
 
-// registerFileLog saves the logs in a file. The function creates a folder called "logs" in the same folder where the application is running.
+// Flush delivers the entries that are pending in the sinks of the service. It should be called before the application
+// stops, so the sinks that send the logs in batches do not lose them.
+func (s Service) Flush() {
+	if s.sampler != nil {
+		if report, ok := s.sampler.report(time.Now(), true); ok {
+			s.registerOrchestrator(s.With(report...).internalEntry(LevelWarning, samplingMessage))
+		}
+	}
+	for _, sink := range append([]Sink{s.webhook}, s.Sinks...) {
+		flusher, ok := sink.(Flusher)
+		if !ok {
+			continue
+		}
+		if err := flusher.Flush(); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+// Close flushes and closes the sinks of the service, the ones that implement io.Closer, and stops the deduplication
+// and the rate limit of the URL, so their pending summaries and digests are sent. It should be called when the
+// application stops, instead of Flush. The service and its copies should not be used after it is closed.
+func (s Service) Close() error {
+	if s.sampler != nil {
+		if report, ok := s.sampler.report(time.Now(), true); ok {
+			s.registerOrchestrator(s.With(report...).internalEntry(LevelWarning, samplingMessage))
+		}
+	}
+	var errs []error
+	for _, sink := range append([]Sink{s.webhook}, s.Sinks...) {
+		var err error
+		switch sink := sink.(type) {
+		case io.Closer:
+			err = sink.Close()
+		case Flusher:
+			err = sink.Flush()
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// registerFileLog saves the logs in a file. The function creates the Dir of the service if it does not exist.
 // The name of the file will be the name of the application. If the name of the application is not provided, the name of the file will be "LOGS".
-// The logs will be saved in the file with the date of the day.
-func (s Service) registerFileLog(content string) {
-	_, err := os.Stat(pathLogs)
+// The logs will be saved in the file with the date of the day. They are also printed in the console, colorized with
+// FormatPretty, and the file always keeps the Content of the entry without colors.
+func (s Service) registerFileLog(entry Entry) {
+	dir := filepath.Dir(s.fileName)
+	_, err := os.Stat(dir)
 	if os.IsNotExist(err) {
-		err = os.Mkdir(pathLogs, 0755)
+		err = os.MkdirAll(dir, 0755)
 		if err != nil {
 			fmt.Println(err)
 			return
@@ -190,12 +516,14 @@ func (s Service) registerFileLog(content string) {
 			fmt.Println(err)
 		}
 	}(file)
-	mw := io.MultiWriter(os.Stdout, file)
-	_, err = fmt.Fprintln(mw, content)
-	if err != nil {
-		return
+	if s.Format == FormatPretty {
+		fmt.Println(prettyContent(entry, s.ShowDate, stdoutColor()))
+	} else {
+		fmt.Println(entry.Content)
 	}
-
+	start := time.Now()
+	n, err := fmt.Fprintln(file, entry.Content)
+	metrics.delivered(sinkFileLog, n, start, err)
 }
 
 // postLog send the log to the URL specified in the configuration. It is used to send the logs to a server.
@@ -206,102 +534,156 @@ func (s Service) postLog(content string) {
 		fmt.Println("error marshaling SendInfo:", err)
 		return
 	}
-	_, err = http.Post(s.URL, "application/json", bytes.NewBuffer(jsonData))
+	start := time.Now()
+	response, err := http.Post(s.URL, "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
+		metrics.delivered(sinkURL, 0, start, err)
 		fmt.Println(err)
+		return
 	}
+	_ = response.Body.Close()
+	if response.StatusCode >= http.StatusMultipleChoices {
+		err = fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+	metrics.delivered(sinkURL, len(jsonData), start, err)
 }
 
 // Trace is the function that registers the logs with the level Trace. It receives the content of the log.
 // It can receive extra information. It is optional.
 func (s Service) Trace(message string, extraMessage ...string) {
-	s.registerOrchestrator(s.logBuilder(logTrace, getLevelCaller(extraMessage...), message, extraMessage...))
+	if !s.Enabled(LevelTrace) {
+		return
+	}
+	s.registerOrchestrator(s.logBuilder(LevelTrace, message, extraMessage...))
 }
 
 // Debug is the function that registers the logs with the level Debug. It receives the content of the log.
 // It can receive extra information. It is optional.
 func (s Service) Debug(message string, extraMessage ...string) {
-	s.registerOrchestrator(s.logBuilder(logDebug, getLevelCaller(extraMessage...), message, extraMessage...))
+	if !s.Enabled(LevelDebug) {
+		return
+	}
+	s.registerOrchestrator(s.logBuilder(LevelDebug, message, extraMessage...))
 }
 
 // Info is the function that registers the logs with the level Info. It receives the content of the log.
 // It can receive extra information. It is optional.
 func (s Service) Info(message string, extraMessage ...string) {
-	s.registerOrchestrator(s.logBuilder(logInfo, getLevelCaller(extraMessage...), message, extraMessage...))
+	if !s.Enabled(LevelInfo) {
+		return
+	}
+	s.registerOrchestrator(s.logBuilder(LevelInfo, message, extraMessage...))
 }
 
 // Notice is the function that registers the logs with the level Notice. It receives the content of the log.
 // It can receive extra information. It is optional.
 func (s Service) Notice(message string, extraMessage ...string) {
-	s.registerOrchestrator(s.logBuilder(logNotice, getLevelCaller(extraMessage...), message, extraMessage...))
+	if !s.Enabled(LevelNotice) {
+		return
+	}
+	s.registerOrchestrator(s.logBuilder(LevelNotice, message, extraMessage...))
 }
 
 // Warning is the function that registers the logs with the level Warning. It receives the content of the log.
 // It can receive extra information. It is optional.
 func (s Service) Warning(message string, extraMessage ...string) {
-	s.registerOrchestrator(s.logBuilder(logWarning, getLevelCaller(extraMessage...), message, extraMessage...))
+	if !s.Enabled(LevelWarning) {
+		return
+	}
+	s.registerOrchestrator(s.logBuilder(LevelWarning, message, extraMessage...))
 }
 
 // Error is the function that registers the logs with the level Error. It receives the content of the log.
 // It can receive extra information. It is optional.
 func (s Service) Error(message string, extraMessage ...string) {
-	s.registerOrchestrator(s.logBuilder(logError, getLevelCaller(extraMessage...), message, extraMessage...))
+	if !s.Enabled(LevelError) {
+		return
+	}
+	s.registerOrchestrator(s.logBuilder(LevelError, message, extraMessage...))
+}
+
+// Panic is the function that registers the logs with the level Panic. It receives the content of the log.
+// It can receive extra information. It is optional. After the log is registered and the sinks are flushed,
+// it panics with the message of the log.
+func (s Service) Panic(message string, extraMessage ...string) {
+	s.terminate(s.logBuilder(LevelPanic, message, extraMessage...))
 }
 
 // Fatal is the function that registers the logs with the level Fatal. It receives the content of the log.
-// It can receive extra information. It is optional.
+// It can receive extra information. It is optional. After the log is registered and the sinks are flushed,
+// the application exits with the ExitCode of the service.
 func (s Service) Fatal(message string, extraMessage ...string) {
-	s.registerOrchestrator(s.logBuilder(logFatal, getLevelCaller(extraMessage...), message, extraMessage...))
+	s.terminate(s.logBuilder(LevelFatal, message, extraMessage...))
+}
+
+// terminate registers the entry of the level Panic or Fatal, flushes the sinks and then panics with the message
+// of the entry or exits the application. It is used internally.
+func (s Service) terminate(entry Entry) {
+	s.registerOrchestrator(entry)
+	s.Flush()
+	if entry.Level == LevelPanic {
+		panic(entry.Message)
+	}
+	s.exit()
+}
+
+// exit calls the ExitFunc of the service with its ExitCode. The services that were not created with NewService exit with os.Exit.
+func (s Service) exit() {
+	code := s.ExitCode
+	if code == 0 {
+		code = 1
+	}
+	if s.ExitFunc == nil {
+		os.Exit(code)
+	}
+	s.ExitFunc(code)
 }
 
 // Trace is the function that registers the logs with the level Trace. It receives the content of the log.
 // It can receive extra information. It is optional. The logs will be registered in the DefaultService.
 func Trace(message string) {
-	DefaultService.Trace(message, callerDefault)
+	DefaultService.Trace(message)
 }
 
 // Debug is the function that registers the logs with the level Debug. It receives the content of the log.
 // It can receive extra information. It is optional. The logs will be registered in the DefaultService.
 func Debug(message string) {
-	DefaultService.Debug(message, callerDefault)
+	DefaultService.Debug(message)
 }
 
 // Info is the function that registers the logs with the level Info. It receives the content of the log.
 // It can receive extra information. It is optional. The logs will be registered in the DefaultService.
 func Info(message string) {
-	DefaultService.Info(message, callerDefault)
+	DefaultService.Info(message)
 }
 
 // Notice is the function that registers the logs with the level Notice. It receives the content of the log.
 // It can receive extra information. It is optional. The logs will be registered in the DefaultService.
 func Notice(message string) {
-	DefaultService.Notice(message, callerDefault)
+	DefaultService.Notice(message)
 }
 
 // Warning is the function that registers the logs with the level Warning. It receives the content of the log.
 // It can receive extra information. It is optional. The logs will be registered in the DefaultService.
 func Warning(message string) {
-	DefaultService.Warning(message, callerDefault)
+	DefaultService.Warning(message)
 }
 
 // Error is the function that registers the logs with the level Error. It receives the content of the log.
 // It can receive extra information. It is optional. The logs will be registered in the DefaultService.
 func Error(message string) {
-	DefaultService.Error(message, callerDefault)
+	DefaultService.Error(message)
 }
 
-// Fatal is the function that registers the logs with the level Fatal. It receives the content of the log.
-// It can receive extra information. It is optional. The logs will be registered in the DefaultService.
-func Fatal(message string) {
-	DefaultService.Fatal(message, callerDefault)
+// Panic is the function that registers the logs with the level Panic. It receives the content of the log.
+// The logs will be registered in the DefaultService. After the log is registered, it panics with the message.
+func Panic(message string) {
+	DefaultService.Panic(message)
 }
 
-// getLevelCaller is the function that returns the level of the caller. It is used internally.
-func getLevelCaller(extraMessage ...string) string {
-	if len(extraMessage) > 0 {
-		if extraMessage[0] == callerDefault {
-			return callerDefault
-		}
-	}
-	return caller
+// Fatal is the function that registers the logs with the level Fatal. It receives the content of the log.
+// The logs will be registered in the DefaultService. After the log is registered, the application exits
+// with the ExitCode of the DefaultService.
+func Fatal(message string) {
+	DefaultService.Fatal(message)
 }
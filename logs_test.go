@@ -1,14 +1,24 @@
 package logs
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_logBuilder(t *testing.T) {
 	type args struct {
-		LogType string
+		LogType      Level
+		ExtraMessage []string
 	}
 	type want struct {
 		Message string
@@ -19,21 +29,31 @@ func Test_logBuilder(t *testing.T) {
 		want want
 	}{
 		{
-			name: "logBuilder when logInfo is called",
+			name: "logBuilder when LevelInfo is called",
 			args: args{
-				LogType: logInfo,
+				LogType: LevelInfo,
 			},
 			want: want{
 				Message: "[LOGS]-[INFO] message ",
 			},
 		},
 		{
-			name: "logBuilder when logTrace is called",
+			name: "logBuilder when LevelTrace is called",
 			args: args{
-				LogType: logTrace,
+				LogType: LevelTrace,
 			},
 			want: want{
-				Message: "[LOGS]-[TRACE] testing.go:1446:tRunner(): message ",
+				Message: "[LOGS]-[TRACE] logs_test.go:{line}:func1(): message ",
+			},
+		},
+		{
+			name: "logBuilder when the extra message is 4",
+			args: args{
+				LogType:      LevelError,
+				ExtraMessage: []string{"4"},
+			},
+			want: want{
+				Message: "[LOGS]-[ERROR] logs_test.go:{line}:func1(): message 4",
 			},
 		},
 	}
@@ -47,20 +67,22 @@ func Test_logBuilder(t *testing.T) {
 				ShowDate: false,
 				ShowTime: false,
 			})
-			messageLogBuilder := logService.logBuilder(tt.args.LogType, caller, "message")
+			_, _, line, _ := runtime.Caller(0)
+			messageLogBuilder := logService.logBuilder(tt.args.LogType, "message", tt.args.ExtraMessage...)
 
-			assert.Equal(t, tt.want.Message, messageLogBuilder)
+			want := strings.ReplaceAll(tt.want.Message, "{line}", strconv.Itoa(line+1))
+			assert.Equal(t, want, messageLogBuilder.Content)
 		})
 	}
 }
 
 func Test_logDecorator(t *testing.T) {
 	type args struct {
-		MessageLevel string
-		CallerLevel  string
+		CallerFormat CallerFormat
 	}
 	type want struct {
-		Message string
+		File     string
+		Function string
 	}
 	tests := []struct {
 		name string
@@ -68,23 +90,42 @@ func Test_logDecorator(t *testing.T) {
 		want want
 	}{
 		{
-			name: "logDecorator when callerLevel is 3",
+			name: "logDecorator when CallerFormat is CallerFileName",
 			args: args{
-				MessageLevel: logInfo,
-				CallerLevel:  caller,
+				CallerFormat: CallerFileName,
 			},
 			want: want{
-				Message: "[LOGS]-[INFO] asm_amd64.s:1594:goexit(): message ",
+				File:     "logs_test.go",
+				Function: "func1",
 			},
 		},
 		{
-			name: "logDecorator when callerLevel is 4",
+			name: "logDecorator when CallerFormat is CallerShortPath",
 			args: args{
-				MessageLevel: logInfo,
-				CallerLevel:  callerDefault,
+				CallerFormat: CallerShortPath,
 			},
 			want: want{
-				Message: "[LOGS]-[INFO] :0:(): message ",
+				File:     filepath.Join(filepath.Base(packageDir), "logs_test.go"),
+				Function: "func1",
+			},
+		},
+		{
+			name: "logDecorator when CallerFormat is CallerFullPath",
+			args: args{
+				CallerFormat: CallerFullPath,
+			},
+			want: want{
+				File:     filepath.Join(packageDir, "logs_test.go"),
+				Function: "github.com/fsandov/logs.Test_logDecorator.func1",
+			},
+		},
+		{
+			name: "logDecorator when CallerFormat is CallerFunctionName",
+			args: args{
+				CallerFormat: CallerFunctionName,
+			},
+			want: want{
+				Function: "github.com/fsandov/logs.Test_logDecorator.func1",
 			},
 		},
 	}
@@ -92,58 +133,67 @@ func Test_logDecorator(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			logService := NewService(Service{
-				NameApp:  "",
-				URL:      "",
-				FileLog:  false,
-				fileName: "",
-				ShowDate: false,
-				ShowTime: false,
+				CallerFormat: tt.args.CallerFormat,
 			})
-			messageLogBuilder := logService.logDecorator(tt.args.MessageLevel, tt.args.CallerLevel, "message")
+			entry := logService.logDecorator(Entry{Level: LevelInfo})
 
-			assert.Equal(t, tt.want.Message, messageLogBuilder)
+			assert.Equal(t, tt.want.File, entry.File)
+			assert.Equal(t, tt.want.Function, entry.Function)
 		})
 	}
 
 }
 
-func Test_getLevelCaller(t *testing.T) {
-	type args struct {
-		extraMessage []string
-	}
+// logWrapper registers a log on behalf of its caller, without marking itself as a helper.
+func logWrapper(logService Service) Entry {
+	return logService.logBuilder(LevelError, "message")
+}
+
+// logHelper registers a log on behalf of its caller and marks itself as a helper.
+func logHelper(logService Service) Entry {
+	Helper()
+	return logService.logBuilder(LevelError, "message")
+}
+
+func TestService_caller(t *testing.T) {
 	type want struct {
-		Level string
+		Function string
 	}
 	tests := []struct {
 		name string
-		args args
+		log  func(logService Service) Entry
 		want want
 	}{
 		{
-			name: "getLevelCaller when level is 3",
-			args: args{
-				extraMessage: []string{"3"},
-			},
+			name: "caller when the service is wrapped",
+			log:  logWrapper,
 			want: want{
-				Level: caller,
+				Function: "logWrapper",
 			},
 		},
 		{
-			name: "getLevelCaller when level is 4",
-			args: args{
-				extraMessage: []string{"4"},
+			name: "caller when the service is wrapped and skips a frame",
+			log: func(logService Service) Entry {
+				return logWrapper(logService.AddCallerSkip(1))
 			},
 			want: want{
-				Level: callerDefault,
+				Function: "func1",
+			},
+		},
+		{
+			name: "caller when the service is wrapped by a helper",
+			log:  logHelper,
+			want: want{
+				Function: "func2",
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			levelCaller := getLevelCaller(tt.args.extraMessage...)
+			entry := tt.log(NewService(Service{}))
 
-			assert.Equal(t, tt.want.Level, levelCaller)
+			assert.Equal(t, tt.want.Function, entry.Function)
 		})
 	}
 }
@@ -386,23 +436,52 @@ func Test_Error(t *testing.T) {
 	}
 }
 
+// flushSink is a sink that records the entries it receives and the moment they are flushed. It is used in the tests.
+type flushSink struct {
+	recordSink
+	flushed int
+}
+
+func (f *flushSink) Flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flushed = len(f.entries)
+	return nil
+}
+
 func TestService_Fatal(t *testing.T) {
 	type args struct {
-		message string
+		message  string
+		exitCode int
+	}
+	type want struct {
+		code int
 	}
 	tests := []struct {
 		name string
 		args args
+		want want
 	}{
 		{
 			name: "Fatal",
 			args: args{
 				message: "message",
 			},
+			want: want{code: 1},
+		},
+		{
+			name: "Fatal with an ExitCode",
+			args: args{
+				message:  "message",
+				exitCode: 3,
+			},
+			want: want{code: 3},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			sink := &flushSink{}
+			code := -1
 			logService := NewService(Service{
 				NameApp:  "",
 				URL:      "",
@@ -410,8 +489,17 @@ func TestService_Fatal(t *testing.T) {
 				fileName: "",
 				ShowDate: false,
 				ShowTime: false,
+				Sinks:    []Sink{sink},
+				ExitCode: tt.args.exitCode,
+				ExitFunc: func(exitCode int) {
+					code = exitCode
+				},
 			})
 			logService.Fatal(tt.args.message)
+
+			assert.Equal(t, tt.want.code, code)
+			assert.Equal(t, LevelFatal, sink.entries[0].Level)
+			assert.Equal(t, 1, sink.flushed)
 		})
 	}
 }
@@ -433,7 +521,68 @@ func Test_Fatal(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			exitFunc := DefaultService.ExitFunc
+			defer func() { DefaultService.ExitFunc = exitFunc }()
+			code := -1
+			DefaultService.ExitFunc = func(exitCode int) {
+				code = exitCode
+			}
 			Fatal(tt.args.message)
+
+			assert.Equal(t, 1, code)
+		})
+	}
+}
+
+func TestService_Panic(t *testing.T) {
+	type args struct {
+		message string
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{
+			name: "Panic",
+			args: args{
+				message: "message",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &flushSink{}
+			logService := NewService(Service{Sinks: []Sink{sink}})
+
+			assert.PanicsWithValue(t, tt.args.message, func() {
+				logService.Panic(tt.args.message)
+			})
+			assert.Equal(t, LevelPanic, sink.entries[0].Level)
+			assert.Equal(t, 1, sink.flushed)
+		})
+	}
+}
+
+func Test_Panic(t *testing.T) {
+	type args struct {
+		message string
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{
+			name: "Panic",
+			args: args{
+				message: "message",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.PanicsWithValue(t, tt.args.message, func() {
+				Panic(tt.args.message)
+			})
 		})
 	}
 }
@@ -541,3 +690,67 @@ func Test_Notice(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Log(t *testing.T) {
+	tests := []struct {
+		name  string
+		level Level
+	}{
+		{name: "Log with Info", level: LevelInfo},
+		{name: "Log with Fatal", level: LevelFatal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordSink{}
+			exited := false
+			logService := NewService(Service{NameApp: "api", StackLevel: LevelTrace, Sinks: []Sink{sink}, ExitFunc: func(int) { exited = true }})
+
+			logService.Log(tt.level, "GET /users 200", Any("status", 200))
+
+			require.Len(t, sink.entries, 1)
+			assert.Equal(t, tt.level, sink.entries[0].Level)
+			assert.Empty(t, sink.entries[0].File)
+			assert.Empty(t, sink.entries[0].Stack)
+			assert.Equal(t, "[api]-["+string(tt.level)+"] GET /users 200 status=200", sink.entries[0].Content)
+			assert.False(t, exited)
+		})
+	}
+}
+
+// closeSink is a sink that records when it is closed.
+type closeSink struct {
+	recordSink
+	closed bool
+}
+
+func (c *closeSink) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestService_Close(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	closer := &closeSink{}
+	flusher := &flushSink{}
+	logService := NewService(Service{URL: server.URL, URLDedup: time.Hour, Sinks: []Sink{closer, flusher}})
+
+	for i := 0; i < 3; i++ {
+		logService.Error("db down")
+	}
+	assert.Equal(t, int32(1), requests.Load())
+	assert.NoError(t, logService.Close())
+
+	assert.Equal(t, int32(2), requests.Load())
+	assert.True(t, closer.closed)
+	assert.Equal(t, 3, flusher.flushed)
+	select {
+	case <-logService.webhook.(*DedupSink).done:
+	default:
+		t.Error("the DedupSink of the URL was not closed")
+	}
+}
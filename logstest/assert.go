@@ -0,0 +1,41 @@
+package logstest
+
+import (
+	"testing"
+
+	"github.com/fsandov/logs"
+)
+
+// AssertLogged checks that the observer recorded an entry with the level and the message provided. Otherwise, the test
+// fails with the entries recorded. It returns whether the assertion passed.
+func AssertLogged(tb testing.TB, observer *Observer, level logs.Level, message string) bool {
+	tb.Helper()
+	if observer.FilterLevel(level).FilterMessage(message).Len() > 0 {
+		return true
+	}
+	tb.Errorf("expected a log with the level %s and the message %q, the logs are:\n%s", level, message, observer)
+	return false
+}
+
+// AssertNotLogged checks that the observer did not record an entry with the level and the message provided.
+// Otherwise, the test fails with the entries recorded. It returns whether the assertion passed.
+func AssertNotLogged(tb testing.TB, observer *Observer, level logs.Level, message string) bool {
+	tb.Helper()
+	if observer.FilterLevel(level).FilterMessage(message).Len() == 0 {
+		return true
+	}
+	tb.Errorf("expected no logs with the level %s and the message %q, the logs are:\n%s", level, message, observer)
+	return false
+}
+
+// AssertLoggedField checks that the observer recorded an entry with the level and the message provided and with a
+// field with the key and the value provided. Otherwise, the test fails with the entries recorded.
+// It returns whether the assertion passed.
+func AssertLoggedField(tb testing.TB, observer *Observer, level logs.Level, message string, key string, value interface{}) bool {
+	tb.Helper()
+	if observer.FilterLevel(level).FilterMessage(message).FilterField(key, value).Len() > 0 {
+		return true
+	}
+	tb.Errorf("expected a log with the level %s, the message %q and the field %s=%v, the logs are:\n%s", level, message, key, value, observer)
+	return false
+}
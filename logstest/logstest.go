@@ -0,0 +1,140 @@
+// Package logstest provides the sinks and the assertions to test the logs registered through a logs.Service.
+// The Observer records the entries so the tests can check what was logged without comparing the formatted text,
+// and the TBSink prints the logs in the output of the test.
+package logstest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fsandov/logs"
+)
+
+// Observer is a sink that records the entries it receives in memory. The entries can be filtered by level, message
+// and field. It is safe to use from several goroutines. Example:
+//
+//	observer := logstest.NewObserver()
+//	service := logs.NewService(logs.Service{Sinks: []logs.Sink{observer}})
+//	service.Error("db down")
+//	logstest.AssertLogged(t, observer, logs.LevelError, "db down")
+type Observer struct {
+	mu      sync.Mutex
+	entries []logs.Entry
+}
+
+// NewObserver returns a new Observer without entries.
+func NewObserver() *Observer {
+	return &Observer{}
+}
+
+// NewService returns a new logs.Service with the name provided that writes to an Observer and to a TBSink of the test,
+// and the Observer. The logs are not printed in the console or saved in a file.
+func NewService(tb testing.TB, nameApp string) (logs.Service, *Observer) {
+	observer := NewObserver()
+	service := logs.NewService(logs.Service{NameApp: nameApp, Sinks: []logs.Sink{observer, NewTBSink(tb)}})
+	return service, observer
+}
+
+// Write records the entry.
+func (o *Observer) Write(entry logs.Entry) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = append(o.entries, entry)
+	return nil
+}
+
+// Entries returns a copy of the entries recorded, in the order they were written.
+func (o *Observer) Entries() []logs.Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entries := make([]logs.Entry, len(o.entries))
+	copy(entries, o.entries)
+	return entries
+}
+
+// Len returns the number of entries recorded.
+func (o *Observer) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+// Reset removes the entries recorded.
+func (o *Observer) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = nil
+}
+
+// FilterLevel returns a new Observer with the entries of the level provided.
+func (o *Observer) FilterLevel(level logs.Level) *Observer {
+	return o.filter(func(entry logs.Entry) bool {
+		return entry.Level == level
+	})
+}
+
+// FilterMessage returns a new Observer with the entries of the message provided.
+func (o *Observer) FilterMessage(message string) *Observer {
+	return o.filter(func(entry logs.Entry) bool {
+		return entry.Message == message
+	})
+}
+
+// FilterMessageSnippet returns a new Observer with the entries whose message contains the text provided.
+func (o *Observer) FilterMessageSnippet(snippet string) *Observer {
+	return o.filter(func(entry logs.Entry) bool {
+		return strings.Contains(entry.Message, snippet)
+	})
+}
+
+// FilterField returns a new Observer with the entries that have a field with the key and the value provided.
+// The values are compared with reflect.DeepEqual, so the type of the value must be the same. Example: 3 is not int64(3)
+func (o *Observer) FilterField(key string, value interface{}) *Observer {
+	return o.filter(func(entry logs.Entry) bool {
+		for _, field := range entry.Fields {
+			if field.Key == key && reflect.DeepEqual(field.Value, value) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FilterFieldKey returns a new Observer with the entries that have a field with the key provided.
+func (o *Observer) FilterFieldKey(key string) *Observer {
+	return o.filter(func(entry logs.Entry) bool {
+		for _, field := range entry.Fields {
+			if field.Key == key {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// filter returns a new Observer with the entries that match the function provided.
+func (o *Observer) filter(match func(entry logs.Entry) bool) *Observer {
+	filtered := &Observer{}
+	for _, entry := range o.Entries() {
+		if match(entry) {
+			filtered.entries = append(filtered.entries, entry)
+		}
+	}
+	return filtered
+}
+
+// String returns the entries recorded, one per line, as they are printed in the console.
+func (o *Observer) String() string {
+	entries := o.Entries()
+	if len(entries) == 0 {
+		return "no logs were registered"
+	}
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = fmt.Sprintf("%d: %s", i, strings.TrimSpace(entry.Content))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,167 @@
+package logstest
+
+import (
+	"testing"
+
+	"github.com/fsandov/logs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserver_Filter(t *testing.T) {
+	observer := NewObserver()
+	service := logs.NewService(logs.Service{Sinks: []logs.Sink{observer}})
+
+	service.Infow("user created", "user", "fsandov", "attempts", 3)
+	service.Errorw("db down", "attempts", 3)
+	service.Error("db down again")
+
+	tests := []struct {
+		name     string
+		observer *Observer
+		messages []string
+	}{
+		{
+			name:     "FilterLevel",
+			observer: observer.FilterLevel(logs.LevelError),
+			messages: []string{"db down", "db down again"},
+		},
+		{
+			name:     "FilterMessage",
+			observer: observer.FilterMessage("db down"),
+			messages: []string{"db down"},
+		},
+		{
+			name:     "FilterMessageSnippet",
+			observer: observer.FilterMessageSnippet("down"),
+			messages: []string{"db down", "db down again"},
+		},
+		{
+			name:     "FilterField",
+			observer: observer.FilterField("attempts", 3),
+			messages: []string{"user created", "db down"},
+		},
+		{
+			name:     "FilterField with another type",
+			observer: observer.FilterField("attempts", int64(3)),
+		},
+		{
+			name:     "FilterFieldKey",
+			observer: observer.FilterFieldKey("user"),
+			messages: []string{"user created"},
+		},
+		{
+			name:     "Filters chained",
+			observer: observer.FilterLevel(logs.LevelError).FilterFieldKey("attempts"),
+			messages: []string{"db down"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, entry := range tt.observer.Entries() {
+				messages = append(messages, entry.Message)
+			}
+
+			assert.Equal(t, tt.messages, messages)
+		})
+	}
+}
+
+func TestObserver_Reset(t *testing.T) {
+	service, observer := NewService(t, "api")
+
+	service.Info("message")
+	assert.Equal(t, 1, observer.Len())
+	assert.Equal(t, "0: [api]-[INFO] message", observer.String())
+	observer.Reset()
+
+	assert.Equal(t, 0, observer.Len())
+	assert.Equal(t, "no logs were registered", observer.String())
+}
+
+// fakeTB is a testing.TB that records the errors and the logs instead of failing the test.
+type fakeTB struct {
+	testing.TB
+	errors   []string
+	logs     []string
+	cleanups []func()
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, format)
+}
+
+func (f *fakeTB) Log(args ...interface{}) {
+	f.logs = append(f.logs, args[0].(string))
+}
+
+func (f *fakeTB) Cleanup(cleanup func()) {
+	f.cleanups = append(f.cleanups, cleanup)
+}
+
+func TestAssertLogged(t *testing.T) {
+	observer := NewObserver()
+	service := logs.NewService(logs.Service{Sinks: []logs.Sink{observer}})
+	service.With(logs.Any("db", "users")).Error("db down")
+
+	tests := []struct {
+		name   string
+		assert func(tb testing.TB) bool
+		want   bool
+	}{
+		{
+			name:   "AssertLogged with the log",
+			assert: func(tb testing.TB) bool { return AssertLogged(tb, observer, logs.LevelError, "db down") },
+			want:   true,
+		},
+		{
+			name:   "AssertLogged with another level",
+			assert: func(tb testing.TB) bool { return AssertLogged(tb, observer, logs.LevelWarning, "db down") },
+		},
+		{
+			name:   "AssertNotLogged with the log",
+			assert: func(tb testing.TB) bool { return AssertNotLogged(tb, observer, logs.LevelError, "db down") },
+		},
+		{
+			name:   "AssertNotLogged without the log",
+			assert: func(tb testing.TB) bool { return AssertNotLogged(tb, observer, logs.LevelError, "db up") },
+			want:   true,
+		},
+		{
+			name: "AssertLoggedField with the field",
+			assert: func(tb testing.TB) bool {
+				return AssertLoggedField(tb, observer, logs.LevelError, "db down", "db", "users")
+			},
+			want: true,
+		},
+		{
+			name: "AssertLoggedField with another value",
+			assert: func(tb testing.TB) bool {
+				return AssertLoggedField(tb, observer, logs.LevelError, "db down", "db", "orders")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := &fakeTB{TB: t}
+
+			assert.Equal(t, tt.want, tt.assert(tb))
+			assert.Equal(t, !tt.want, len(tb.errors) == 1)
+		})
+	}
+}
+
+func TestTBSink_Write(t *testing.T) {
+	tb := &fakeTB{TB: t}
+	service := logs.NewService(logs.Service{NameApp: "api", Sinks: []logs.Sink{NewTBSink(tb)}})
+
+	service.Info("message")
+	for _, cleanup := range tb.cleanups {
+		cleanup()
+	}
+	service.Info("after the test")
+
+	assert.Equal(t, []string{"[api]-[INFO] message"}, tb.logs)
+}
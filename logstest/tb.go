@@ -0,0 +1,39 @@
+package logstest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fsandov/logs"
+)
+
+// TBSink is a sink that prints the logs in the output of a test with t.Log, so they are shown only when the test fails
+// or runs with -v. The logs written after the test finished are dropped, because t.Log can not be called anymore.
+type TBSink struct {
+	tb       testing.TB
+	mu       sync.Mutex
+	finished bool
+}
+
+// NewTBSink returns a new TBSink that prints the logs in the output of the test provided. Example: logstest.NewTBSink(t)
+func NewTBSink(tb testing.TB) *TBSink {
+	sink := &TBSink{tb: tb}
+	tb.Cleanup(func() {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		sink.finished = true
+	})
+	return sink
+}
+
+// Write prints the content of the entry in the output of the test.
+func (s *TBSink) Write(entry logs.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.finished {
+		return nil
+	}
+	s.tb.Log(strings.TrimSuffix(entry.Content, " "))
+	return nil
+}
@@ -0,0 +1,226 @@
+package logs
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Names of the reasons of the logs dropped, in the metric logs_dropped_total.
+const (
+	dropSampled      = "sampled"
+	dropDeduplicated = "deduplicated"
+	dropRateLimited  = "rate_limited"
+	dropQueueFull    = "queue_full"
+)
+
+// Names of the destinations of the logs, in the label sink of the metrics.
+const (
+	sinkURL     = "url"
+	sinkFileLog = "file_log"
+	sinkConsole = "console"
+	sinkFile    = "file"
+	sinkWebhook = "webhook"
+	sinkSyslog  = "syslog"
+	sinkGELF    = "gelf"
+	sinkSplunk  = "splunk"
+	sinkOTLP    = "otlp"
+	sinkLimit   = "rate_limit"
+)
+
+// metricsLatencyBuckets are the upper bounds, in seconds, of the buckets of the delivery latency.
+var metricsLatencyBuckets = []float64{0.0005, 0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metrics are the metrics of the logs of all the services and the sinks of the application.
+var metrics = newMetricsRegistry()
+
+// metricsRegistry keeps the metrics of the logs. They are exposed by the MetricsHandler.
+type metricsRegistry struct {
+	records  *metricVec
+	dropped  *metricVec
+	bytes    *metricVec
+	failures *metricVec
+	retries  *metricVec
+	queue    *metricVec
+	latency  *metricVec
+}
+
+// newMetricsRegistry returns a registry without values.
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{
+		records:  newMetricVec("logs_records_total", "The number of logs registered, by app and level.", metricCounter, "app", "level"),
+		dropped:  newMetricVec("logs_dropped_total", "The number of logs dropped by the sampling, the deduplication, the rate limits or the full queues.", metricCounter, "app", "reason"),
+		bytes:    newMetricVec("logs_sink_bytes_total", "The number of bytes delivered to the destinations of the logs.", metricCounter, "sink"),
+		failures: newMetricVec("logs_delivery_failures_total", "The number of deliveries of logs that failed.", metricCounter, "sink"),
+		retries:  newMetricVec("logs_delivery_retries_total", "The number of deliveries of logs that were retried.", metricCounter, "sink"),
+		queue:    newMetricVec("logs_queue_depth", "The number of logs waiting to be delivered.", metricGauge, "sink"),
+		latency:  newMetricVec("logs_delivery_duration_seconds", "The time spent delivering the logs to their destinations.", metricHistogram, "sink"),
+	}
+}
+
+// delivered records a delivery of logs to the sink: the bytes, the time since the start, and the failure.
+func (m *metricsRegistry) delivered(sink string, bytes int, start time.Time, err error) {
+	m.latency.observe(time.Since(start).Seconds(), sink)
+	if err != nil {
+		m.failures.add(1, sink)
+		return
+	}
+	m.bytes.add(float64(bytes), sink)
+}
+
+// MetricsHandler returns a http.Handler that exposes the metrics of the logs in the Prometheus text format.
+// The metrics are shared by all the services and the sinks of the application:
+//   - logs_records_total: the logs registered, by app and level.
+//   - logs_dropped_total: the logs dropped by the sampling, the deduplication, the rate limits or the full queues of the
+//     sinks, by app and reason.
+//   - logs_sink_bytes_total: the bytes delivered to the URL, the file, the console and the sinks, by sink.
+//   - logs_delivery_failures_total and logs_delivery_retries_total: the deliveries that failed and that were retried, by sink.
+//   - logs_queue_depth: the logs waiting in the batches and the digests, by sink.
+//   - logs_delivery_duration_seconds: the histogram of the time of the deliveries, by sink.
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writer := bufio.NewWriter(w)
+		for _, vec := range []*metricVec{
+			metrics.records, metrics.dropped, metrics.bytes, metrics.failures, metrics.retries, metrics.queue, metrics.latency,
+		} {
+			vec.write(writer)
+		}
+		_ = writer.Flush()
+	})
+}
+
+// metricKind is the Prometheus type of a metric.
+type metricKind string
+
+const (
+	metricCounter   metricKind = "counter"
+	metricGauge     metricKind = "gauge"
+	metricHistogram metricKind = "histogram"
+)
+
+// metricVec is a metric with a value for every combination of the values of its labels.
+type metricVec struct {
+	name   string
+	help   string
+	kind   metricKind
+	labels []string
+	// values are the values of the metric by the values of the labels joined with a null character.
+	values sync.Map
+}
+
+// metricValue is the value of a metric for the values of its labels.
+type metricValue struct {
+	labels []string
+	// bits are the bits of the float64 value of a counter or a gauge.
+	bits atomic.Uint64
+	// mu protects the buckets, the sum and the count of a histogram.
+	mu      sync.Mutex
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// newMetricVec returns a metric without values.
+func newMetricVec(name string, help string, kind metricKind, labels ...string) *metricVec {
+	return &metricVec{name: name, help: help, kind: kind, labels: labels}
+}
+
+// value returns the value of the metric for the values of the labels provided. It is created when it does not exist.
+func (v *metricVec) value(labels []string) *metricValue {
+	key := strings.Join(labels, "\x00")
+	if value, ok := v.values.Load(key); ok {
+		return value.(*metricValue)
+	}
+	value, _ := v.values.LoadOrStore(key, &metricValue{labels: labels, buckets: make([]uint64, len(metricsLatencyBuckets))})
+	return value.(*metricValue)
+}
+
+// add adds the delta to the counter or the gauge with the values of the labels provided.
+func (v *metricVec) add(delta float64, labels ...string) {
+	value := v.value(labels)
+	for {
+		bits := value.bits.Load()
+		if value.bits.CompareAndSwap(bits, math.Float64bits(math.Float64frombits(bits)+delta)) {
+			return
+		}
+	}
+}
+
+// observe adds the sample to the histogram with the values of the labels provided.
+func (v *metricVec) observe(sample float64, labels ...string) {
+	value := v.value(labels)
+	value.mu.Lock()
+	defer value.mu.Unlock()
+	for i, bound := range metricsLatencyBuckets {
+		if sample <= bound {
+			value.buckets[i]++
+		}
+	}
+	value.sum += sample
+	value.count++
+}
+
+// write writes the metric in the Prometheus text format. The values are sorted by their labels.
+func (v *metricVec) write(w *bufio.Writer) {
+	var values []*metricValue
+	v.values.Range(func(_, value interface{}) bool {
+		values = append(values, value.(*metricValue))
+		return true
+	})
+	sort.Slice(values, func(i, j int) bool {
+		return strings.Join(values[i].labels, "\x00") < strings.Join(values[j].labels, "\x00")
+	})
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.name, v.help, v.name, v.kind)
+	for _, value := range values {
+		labels := v.labelPairs(value.labels)
+		if v.kind != metricHistogram {
+			fmt.Fprintf(w, "%s%s %s\n", v.name, metricLabels(labels), metricNumber(math.Float64frombits(value.bits.Load())))
+			continue
+		}
+		value.mu.Lock()
+		for i, bound := range metricsLatencyBuckets {
+			bucketLabels := append(labels[:len(labels):len(labels)], "le", metricNumber(bound))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, metricLabels(bucketLabels), value.buckets[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, metricLabels(append(labels[:len(labels):len(labels)], "le", "+Inf")), value.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", v.name, metricLabels(labels), metricNumber(value.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", v.name, metricLabels(labels), value.count)
+		value.mu.Unlock()
+	}
+}
+
+// labelPairs returns the names and the values of the labels, alternated.
+func (v *metricVec) labelPairs(values []string) []string {
+	pairs := make([]string, 0, len(values)*2)
+	for i, name := range v.labels {
+		pairs = append(pairs, name, values[i])
+	}
+	return pairs
+}
+
+// metricLabels returns the labels in the Prometheus text format, from their names and values alternated.
+// Example: {app="api",level="INFO"}
+func metricLabels(pairs []string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	labels := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(pairs[i+1])
+		labels = append(labels, fmt.Sprintf(`%s="%s"`, pairs[i], value))
+	}
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
+// metricNumber returns the number in the Prometheus text format.
+func metricNumber(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,124 @@
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	// The registry is global, so the name of the app is unique in every run of the test.
+	app := "metrics-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	logService := NewService(Service{
+		NameApp:  app,
+		MinLevel: LevelDebug,
+		Sampling: &SamplingConfig{Initial: 1, Thereafter: 100},
+		Sinks: []Sink{
+			NewConsoleSink(ConsoleConfig{Writer: io.Discard}),
+			NewWebhookSink(WebhookConfig{URL: server.URL}),
+			NewRateLimitSink(RateLimitConfig{Sink: &recordSink{}, Rate: 1, Per: time.Hour}),
+		},
+	})
+
+	logService.Trace("dropped by the level")
+	logService.Debug("hot loop")
+	logService.Debug("hot loop")
+	logService.Error("db down")
+
+	recorder := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := recorder.Body.String()
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, body, "# TYPE logs_records_total counter\n")
+	assert.Contains(t, body, `logs_records_total{app="`+app+`",level="DEBUG"} 1`+"\n")
+	assert.Contains(t, body, `logs_records_total{app="`+app+`",level="ERROR"} 1`+"\n")
+	assert.NotContains(t, body, `logs_records_total{app="`+app+`",level="TRACE"}`)
+	assert.Contains(t, body, `logs_dropped_total{app="`+app+`",reason="sampled"} 1`+"\n")
+	assert.Contains(t, body, `logs_dropped_total{app="`+app+`",reason="rate_limited"} 1`+"\n")
+	assert.Contains(t, body, `logs_delivery_failures_total{sink="webhook"}`)
+	assert.Contains(t, body, `logs_sink_bytes_total{sink="console"}`)
+	assert.Contains(t, body, "# TYPE logs_delivery_duration_seconds histogram\n")
+	assert.Contains(t, body, `logs_delivery_duration_seconds_bucket{sink="console",le="+Inf"}`)
+}
+
+func Test_metricVec_write(t *testing.T) {
+	tests := []struct {
+		name string
+		vec  func() *metricVec
+		want string
+	}{
+		{
+			name: "write a counter",
+			vec: func() *metricVec {
+				vec := newMetricVec("test_total", "The test.", metricCounter, "app")
+				vec.add(2, "b")
+				vec.add(1, `a"1\`)
+				vec.add(0.5, "b")
+				return vec
+			},
+			want: "# HELP test_total The test.\n# TYPE test_total counter\n" +
+				`test_total{app="a\"1\\"} 1` + "\n" +
+				`test_total{app="b"} 2.5` + "\n",
+		},
+		{
+			name: "write a gauge",
+			vec: func() *metricVec {
+				vec := newMetricVec("test_depth", "The test.", metricGauge, "sink")
+				vec.add(3, "splunk")
+				vec.add(-3, "splunk")
+				return vec
+			},
+			want: "# HELP test_depth The test.\n# TYPE test_depth gauge\n" + `test_depth{sink="splunk"} 0` + "\n",
+		},
+		{
+			name: "write a histogram",
+			vec: func() *metricVec {
+				vec := newMetricVec("test_seconds", "The test.", metricHistogram, "sink")
+				vec.observe(0.003, "url")
+				vec.observe(20, "url")
+				return vec
+			},
+			want: "# HELP test_seconds The test.\n# TYPE test_seconds histogram\n" +
+				`test_seconds_bucket{sink="url",le="0.0005"} 0` + "\n" +
+				`test_seconds_bucket{sink="url",le="0.001"} 0` + "\n" +
+				`test_seconds_bucket{sink="url",le="0.005"} 1` + "\n" +
+				`test_seconds_bucket{sink="url",le="0.01"} 1` + "\n" +
+				`test_seconds_bucket{sink="url",le="0.025"} 1` + "\n" +
+				`test_seconds_bucket{sink="url",le="0.05"} 1` + "\n" +
+				`test_seconds_bucket{sink="url",le="0.1"} 1` + "\n" +
+				`test_seconds_bucket{sink="url",le="0.25"} 1` + "\n" +
+				`test_seconds_bucket{sink="url",le="0.5"} 1` + "\n" +
+				`test_seconds_bucket{sink="url",le="1"} 1` + "\n" +
+				`test_seconds_bucket{sink="url",le="2.5"} 1` + "\n" +
+				`test_seconds_bucket{sink="url",le="5"} 1` + "\n" +
+				`test_seconds_bucket{sink="url",le="10"} 1` + "\n" +
+				`test_seconds_bucket{sink="url",le="+Inf"} 2` + "\n" +
+				`test_seconds_sum{sink="url"} 20.003` + "\n" +
+				`test_seconds_count{sink="url"} 2` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			writer := bufio.NewWriter(&buffer)
+
+			tt.vec().write(writer)
+
+			require.NoError(t, writer.Flush())
+			assert.Equal(t, tt.want, buffer.String())
+		})
+	}
+}
@@ -0,0 +1,574 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// OTLPEncodingProtobuf sends the logs encoded with protobuf. It is the default encoding.
+	OTLPEncodingProtobuf = "protobuf"
+	// OTLPEncodingJSON sends the logs encoded with the OTLP JSON mapping.
+	OTLPEncodingJSON = "json"
+	// otlpScopeName is the name of the instrumentation scope of the logs.
+	otlpScopeName = "github.com/fsandov/logs"
+)
+
+// OTLPConfig is the struct that contains the configuration of the OTLPSink. All the fields are optional.
+type OTLPConfig struct {
+	// Endpoint is the URL where the logs are exported. If it is not provided, it will be http://localhost:4318/v1/logs.
+	Endpoint string
+	// Encoding is the encoding of the requests, OTLPEncodingProtobuf or OTLPEncodingJSON.
+	// If it is not provided, it will be OTLPEncodingProtobuf.
+	Encoding string
+	// Headers are extra headers sent with every request, like the authorization of the collector.
+	Headers map[string]string
+	// BatchSize is the number of records sent in a single request. If it is not provided, it will be 100.
+	BatchSize int
+	// FlushInterval is the maximum time a record waits in the batch before it is sent. If it is not provided, it will be 5 seconds.
+	FlushInterval time.Duration
+	// MaxRetries is the number of times a batch is sent again when the collector is unavailable. If it is not provided, it will be 3.
+	MaxRetries int
+	// QueueSize is the number of full batches that wait to be sent in the background. When the queue is full, the new
+	// full batches are dropped and Write returns an error. If it is not provided, it will be 10.
+	QueueSize int
+	// Client is the HTTP client used to send the logs. If it is not provided, http.DefaultClient is used.
+	Client *http.Client
+}
+
+// OTLPSink is a sink that exports the logs as OpenTelemetry LogRecords over HTTP. The name of the application is sent
+// as the service.name resource attribute and the fields as attributes. The fields TraceIDField and SpanIDField
+// are sent as the trace and span of the record. The records are sent in batches in the background, so Flush or Close
+// should be called before the application stops.
+type OTLPSink struct {
+	config  OTLPConfig
+	mu      sync.Mutex
+	entries []Entry
+	batches [][]Entry
+	queued  chan struct{}
+	sendMu  sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewOTLPSink returns a new OTLPSink with the configuration provided. It starts the flush of the batches every FlushInterval.
+func NewOTLPSink(config OTLPConfig) *OTLPSink {
+	if config.Endpoint == "" {
+		config.Endpoint = "http://localhost:4318/v1/logs"
+	}
+	if config.Encoding == "" {
+		config.Encoding = OTLPEncodingProtobuf
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 5 * time.Second
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = 3
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 10
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	sink := &OTLPSink{
+		config: config,
+		queued: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go sink.flushLoop()
+	return sink
+}
+
+// Write adds the entry to the batch. When the batch reaches the BatchSize, it is queued to be exported in the
+// background, so Write does not wait for the collector. It returns an error when the queue is full and the batch is
+// dropped.
+func (o *OTLPSink) Write(entry Entry) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = append(o.entries, entry)
+	metrics.queue.add(1, sinkOTLP)
+	if len(o.entries) < o.config.BatchSize {
+		return nil
+	}
+	entries := o.entries
+	o.entries = nil
+	if len(o.batches) >= o.config.QueueSize {
+		metrics.queue.add(-float64(len(entries)), sinkOTLP)
+		metrics.dropped.add(float64(len(entries)), entry.NameApp, dropQueueFull)
+		return fmt.Errorf("otlp: the queue is full, %d records were dropped", len(entries))
+	}
+	o.batches = append(o.batches, entries)
+	select {
+	case o.queued <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Flush exports the queued batches and the records of the current batch. It waits for the batch that is being
+// exported in the background.
+func (o *OTLPSink) Flush() error {
+	return o.sendBatches(true)
+}
+
+// sendBatches exports the queued batches, and the current batch when current is true. The batches are taken and
+// exported while sendMu is locked, so a call waits for the batches that are being exported by another one.
+func (o *OTLPSink) sendBatches(current bool) error {
+	o.sendMu.Lock()
+	defer o.sendMu.Unlock()
+	o.mu.Lock()
+	batches := o.batches
+	o.batches = nil
+	if current {
+		batches = append(batches, o.entries)
+		o.entries = nil
+	}
+	o.mu.Unlock()
+	var errs []error
+	for _, entries := range batches {
+		if err := o.sendBatch(entries); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendBatch exports the records. It is called with sendMu locked.
+func (o *OTLPSink) sendBatch(entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	metrics.queue.add(-float64(len(entries)), sinkOTLP)
+	request := newOTLPRequest(entries)
+	var body []byte
+	contentType := "application/x-protobuf"
+	if o.config.Encoding == OTLPEncodingJSON {
+		var err error
+		body, err = json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("otlp: error marshaling logs: %w", err)
+		}
+		contentType = "application/json"
+	} else {
+		body = request.marshalProto()
+	}
+	return o.send(body, contentType)
+}
+
+// Close stops the periodic flush and exports the pending records.
+func (o *OTLPSink) Close() error {
+	select {
+	case <-o.stop:
+	default:
+		close(o.stop)
+		<-o.done
+	}
+	return o.Flush()
+}
+
+// flushLoop exports the full batches when they are queued, and the batch every FlushInterval, until the sink is
+// closed.
+func (o *OTLPSink) flushLoop() {
+	defer close(o.done)
+	ticker := time.NewTicker(o.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-o.queued:
+			if err := o.sendBatches(false); err != nil {
+				fmt.Println(err)
+			}
+		case <-ticker.C:
+			if err := o.Flush(); err != nil {
+				fmt.Println(err)
+			}
+		case <-o.stop:
+			return
+		}
+	}
+}
+
+// send posts the body to the endpoint. It retries when the collector answers that it is unavailable or throttled.
+func (o *OTLPSink) send(body []byte, contentType string) error {
+	var err error
+	for attempt := 0; attempt <= o.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+			metrics.retries.add(1, sinkOTLP)
+		}
+		var retry bool
+		start := time.Now()
+		retry, err = o.post(body, contentType)
+		metrics.delivered(sinkOTLP, len(body), start, err)
+		if err == nil || !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// post sends the body to the endpoint once. It returns true when the request can be sent again.
+func (o *OTLPSink) post(body []byte, contentType string) (bool, error) {
+	request, err := http.NewRequest(http.MethodPost, o.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("otlp: %w", err)
+	}
+	request.Header.Set("Content-Type", contentType)
+	for key, value := range o.config.Headers {
+		request.Header.Set(key, value)
+	}
+	response, err := o.config.Client.Do(request)
+	if err != nil {
+		return true, fmt.Errorf("otlp: %w", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	switch response.StatusCode {
+	case http.StatusOK:
+		return false, nil
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true, fmt.Errorf("otlp: collector answered %s", response.Status)
+	default:
+		return false, fmt.Errorf("otlp: collector answered %s", response.Status)
+	}
+}
+
+// severityNumber returns the OpenTelemetry SeverityNumber of the level.
+func (l Level) severityNumber() int {
+	switch l {
+	case LevelTrace:
+		return 1
+	case LevelDebug:
+		return 5
+	case LevelInfo:
+		return 9
+	case LevelNotice:
+		return 10
+	case LevelWarning:
+		return 13
+	case LevelError:
+		return 17
+	case LevelPanic:
+		return 21
+	case LevelFatal:
+		return 24
+	default:
+		return 0
+	}
+}
+
+// otlpRequest is the ExportLogsServiceRequest of the OTLP protocol. The types follow the OTLP JSON mapping
+// and marshal themselves with the protobuf wire format.
+type otlpRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         otlpUint64     `json:"timeUnixNano"`
+	ObservedTimeUnixNano otlpUint64     `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	TraceID              otlpID         `json:"traceId,omitempty"`
+	SpanID               otlpID         `json:"spanId,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *otlpInt64      `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+// otlpUint64 and otlpInt64 are encoded as strings in JSON, as the OTLP JSON mapping requires.
+type otlpUint64 uint64
+
+func (v otlpUint64) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + strconv.FormatUint(uint64(v), 10) + `"`), nil
+}
+
+type otlpInt64 int64
+
+func (v otlpInt64) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + strconv.FormatInt(int64(v), 10) + `"`), nil
+}
+
+// otlpID is a trace or span ID. It is encoded as hex in JSON and as bytes in protobuf.
+type otlpID []byte
+
+func (id otlpID) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + hex.EncodeToString(id) + `"`), nil
+}
+
+// newOTLPRequest returns the request with the entries grouped by the name of the application.
+func newOTLPRequest(entries []Entry) otlpRequest {
+	var request otlpRequest
+	index := map[string]int{}
+	for _, entry := range entries {
+		i, ok := index[entry.NameApp]
+		if !ok {
+			i = len(request.ResourceLogs)
+			index[entry.NameApp] = i
+			request.ResourceLogs = append(request.ResourceLogs, otlpResourceLogs{
+				Resource:  otlpResource{Attributes: []otlpKeyValue{{Key: "service.name", Value: newOTLPValue(entry.NameApp)}}},
+				ScopeLogs: []otlpScopeLogs{{Scope: otlpScope{Name: otlpScopeName}}},
+			})
+		}
+		scope := &request.ResourceLogs[i].ScopeLogs[0]
+		scope.LogRecords = append(scope.LogRecords, newOTLPLogRecord(entry))
+	}
+	return request
+}
+
+// newOTLPLogRecord returns the log record of the entry.
+func newOTLPLogRecord(entry Entry) otlpLogRecord {
+	record := otlpLogRecord{
+		TimeUnixNano:         otlpUint64(entry.Time.UnixNano()),
+		ObservedTimeUnixNano: otlpUint64(time.Now().UnixNano()),
+		SeverityNumber:       entry.Level.severityNumber(),
+		SeverityText:         string(entry.Level),
+		Body:                 newOTLPValue(entry.Message),
+	}
+	if entry.File != "" {
+		record.Attributes = append(record.Attributes,
+			otlpKeyValue{Key: "code.filepath", Value: newOTLPValue(entry.File)},
+			otlpKeyValue{Key: "code.lineno", Value: newOTLPValue(entry.Line)},
+			otlpKeyValue{Key: "code.function", Value: newOTLPValue(entry.Function)},
+		)
+	}
+	if len(entry.Stack) > 0 {
+		record.Attributes = append(record.Attributes, otlpKeyValue{Key: "exception.stacktrace", Value: newOTLPValue(stackText(entry.Stack))})
+	}
+	for _, field := range flatFields(entry.Fields) {
+		switch field.Key {
+		case TraceIDField:
+			if id, err := hex.DecodeString(fmt.Sprint(field.Value)); err == nil && len(id) == 16 {
+				record.TraceID = id
+				continue
+			}
+		case SpanIDField:
+			if id, err := hex.DecodeString(fmt.Sprint(field.Value)); err == nil && len(id) == 8 {
+				record.SpanID = id
+				continue
+			}
+		}
+		record.Attributes = append(record.Attributes, otlpKeyValue{Key: field.Key, Value: newOTLPValue(field.Value)})
+	}
+	return record
+}
+
+// newOTLPValue returns the AnyValue of the value. The values of unknown types are sent as strings.
+func newOTLPValue(value interface{}) otlpAnyValue {
+	switch v := value.(type) {
+	case string:
+		return otlpAnyValue{StringValue: &v}
+	case bool:
+		return otlpAnyValue{BoolValue: &v}
+	case int:
+		i := otlpInt64(v)
+		return otlpAnyValue{IntValue: &i}
+	case int8:
+		i := otlpInt64(v)
+		return otlpAnyValue{IntValue: &i}
+	case int16:
+		i := otlpInt64(v)
+		return otlpAnyValue{IntValue: &i}
+	case int32:
+		i := otlpInt64(v)
+		return otlpAnyValue{IntValue: &i}
+	case int64:
+		i := otlpInt64(v)
+		return otlpAnyValue{IntValue: &i}
+	case uint8:
+		i := otlpInt64(v)
+		return otlpAnyValue{IntValue: &i}
+	case uint16:
+		i := otlpInt64(v)
+		return otlpAnyValue{IntValue: &i}
+	case uint32:
+		i := otlpInt64(v)
+		return otlpAnyValue{IntValue: &i}
+	case float32:
+		f := float64(v)
+		return otlpAnyValue{DoubleValue: &f}
+	case float64:
+		return otlpAnyValue{DoubleValue: &v}
+	case []string:
+		array := &otlpArrayValue{}
+		for _, item := range v {
+			array.Values = append(array.Values, newOTLPValue(item))
+		}
+		return otlpAnyValue{ArrayValue: array}
+	case []interface{}:
+		array := &otlpArrayValue{}
+		for _, item := range v {
+			array.Values = append(array.Values, newOTLPValue(item))
+		}
+		return otlpAnyValue{ArrayValue: array}
+	default:
+		s := fmt.Sprint(v)
+		return otlpAnyValue{StringValue: &s}
+	}
+}
+
+// protoBuffer writes the protobuf wire format.
+type protoBuffer []byte
+
+func (b *protoBuffer) varint(value uint64) {
+	*b = binary.AppendUvarint(*b, value)
+}
+
+func (b *protoBuffer) tag(field int, wireType int) {
+	b.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (b *protoBuffer) bytes(field int, value []byte) {
+	b.tag(field, 2)
+	b.varint(uint64(len(value)))
+	*b = append(*b, value...)
+}
+
+func (b *protoBuffer) string(field int, value string) {
+	if value != "" {
+		b.bytes(field, []byte(value))
+	}
+}
+
+func (b *protoBuffer) fixed64(field int, value uint64) {
+	b.tag(field, 1)
+	*b = binary.LittleEndian.AppendUint64(*b, value)
+}
+
+func (b *protoBuffer) uint(field int, value uint64) {
+	if value != 0 {
+		b.tag(field, 0)
+		b.varint(value)
+	}
+}
+
+func (r otlpRequest) marshalProto() []byte {
+	var b protoBuffer
+	for _, resourceLogs := range r.ResourceLogs {
+		b.bytes(1, resourceLogs.marshalProto())
+	}
+	return b
+}
+
+func (r otlpResourceLogs) marshalProto() []byte {
+	var b, resource protoBuffer
+	for _, attribute := range r.Resource.Attributes {
+		resource.bytes(1, attribute.marshalProto())
+	}
+	b.bytes(1, resource)
+	for _, scopeLogs := range r.ScopeLogs {
+		b.bytes(2, scopeLogs.marshalProto())
+	}
+	return b
+}
+
+func (s otlpScopeLogs) marshalProto() []byte {
+	var b, scope protoBuffer
+	scope.string(1, s.Scope.Name)
+	b.bytes(1, scope)
+	for _, record := range s.LogRecords {
+		b.bytes(2, record.marshalProto())
+	}
+	return b
+}
+
+func (r otlpLogRecord) marshalProto() []byte {
+	var b protoBuffer
+	b.fixed64(1, uint64(r.TimeUnixNano))
+	b.uint(2, uint64(r.SeverityNumber))
+	b.string(3, r.SeverityText)
+	b.bytes(5, r.Body.marshalProto())
+	for _, attribute := range r.Attributes {
+		b.bytes(6, attribute.marshalProto())
+	}
+	if len(r.TraceID) > 0 {
+		b.bytes(9, r.TraceID)
+	}
+	if len(r.SpanID) > 0 {
+		b.bytes(10, r.SpanID)
+	}
+	b.fixed64(11, uint64(r.ObservedTimeUnixNano))
+	return b
+}
+
+func (kv otlpKeyValue) marshalProto() []byte {
+	var b protoBuffer
+	b.string(1, kv.Key)
+	b.bytes(2, kv.Value.marshalProto())
+	return b
+}
+
+func (v otlpAnyValue) marshalProto() []byte {
+	var b protoBuffer
+	switch {
+	case v.StringValue != nil:
+		b.bytes(1, []byte(*v.StringValue))
+	case v.BoolValue != nil:
+		b.tag(2, 0)
+		if *v.BoolValue {
+			b.varint(1)
+		} else {
+			b.varint(0)
+		}
+	case v.IntValue != nil:
+		b.tag(3, 0)
+		b.varint(uint64(*v.IntValue))
+	case v.DoubleValue != nil:
+		b.fixed64(4, math.Float64bits(*v.DoubleValue))
+	case v.ArrayValue != nil:
+		var array protoBuffer
+		for _, value := range v.ArrayValue.Values {
+			array.bytes(1, value.marshalProto())
+		}
+		b.bytes(5, array)
+	}
+	return b
+}
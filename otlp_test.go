@@ -0,0 +1,184 @@
+package logs
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// otlpCollector is a stand-in of an OpenTelemetry collector that keeps the bodies it receives.
+type otlpCollector struct {
+	mu           sync.Mutex
+	contentTypes []string
+	bodies       [][]byte
+	status       []int
+}
+
+func (c *otlpCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	c.contentTypes = append(c.contentTypes, r.Header.Get("Content-Type"))
+	c.bodies = append(c.bodies, body)
+	if len(c.status) > 0 {
+		w.WriteHeader(c.status[0])
+		c.status = c.status[1:]
+	}
+}
+
+// protoFields decodes one level of a protobuf message. The values of varint and fixed fields are returned
+// in little endian bytes.
+func protoFields(t *testing.T, b []byte) map[int][][]byte {
+	t.Helper()
+	fields := map[int][][]byte{}
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		b = b[n:]
+		field := int(key >> 3)
+		switch key & 7 {
+		case 0:
+			value, n := binary.Uvarint(b)
+			b = b[n:]
+			fields[field] = append(fields[field], binary.LittleEndian.AppendUint64(nil, value))
+		case 1:
+			fields[field] = append(fields[field], b[:8])
+			b = b[8:]
+		case 2:
+			size, n := binary.Uvarint(b)
+			b = b[n:]
+			fields[field] = append(fields[field], b[:size])
+			b = b[size:]
+		default:
+			t.Fatalf("unexpected wire type %d", key&7)
+		}
+	}
+	return fields
+}
+
+var otlpTestEntry = Entry{
+	Time:     time.Unix(1700000000, 0),
+	Level:    LevelWarning,
+	NameApp:  "Test",
+	Message:  "message",
+	File:     "main.go",
+	Line:     12,
+	Function: "main",
+	Fields: []Field{
+		Any(TraceIDField, "4bf92f3577b34da6a3ce929d0e0e4736"),
+		Any(SpanIDField, "00f067aa0ba902b7"),
+		Any("user", "fsandov"),
+		Any("attempt", 2),
+	},
+}
+
+func TestOTLPSink_FlushJSON(t *testing.T) {
+	collector := &otlpCollector{}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+	sink := NewOTLPSink(OTLPConfig{Endpoint: server.URL, Encoding: OTLPEncodingJSON, FlushInterval: time.Hour})
+	assert.NoError(t, sink.Write(otlpTestEntry))
+	assert.NoError(t, sink.Close())
+
+	assert.Equal(t, []string{"application/json"}, collector.contentTypes)
+	var request struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []map[string]interface{} `json:"attributes"`
+			} `json:"resource"`
+			ScopeLogs []struct {
+				LogRecords []map[string]interface{} `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	assert.NoError(t, json.Unmarshal(collector.bodies[0], &request))
+	assert.Equal(t, "service.name", request.ResourceLogs[0].Resource.Attributes[0]["key"])
+	record := request.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	assert.Equal(t, "1700000000000000000", record["timeUnixNano"])
+	assert.Equal(t, float64(13), record["severityNumber"])
+	assert.Equal(t, "WARNING", record["severityText"])
+	assert.Equal(t, map[string]interface{}{"stringValue": "message"}, record["body"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["traceId"])
+	assert.Equal(t, "00f067aa0ba902b7", record["spanId"])
+	assert.Len(t, record["attributes"], 5)
+}
+
+func TestOTLPSink_FlushProtobuf(t *testing.T) {
+	collector := &otlpCollector{status: []int{http.StatusServiceUnavailable}}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+	sink := NewOTLPSink(OTLPConfig{Endpoint: server.URL, BatchSize: 1})
+	assert.NoError(t, sink.Write(otlpTestEntry))
+	assert.NoError(t, sink.Close())
+
+	assert.Equal(t, []string{"application/x-protobuf", "application/x-protobuf"}, collector.contentTypes)
+	request := protoFields(t, collector.bodies[1])
+	resourceLogs := protoFields(t, request[1][0])
+	resource := protoFields(t, resourceLogs[1][0])
+	serviceName := protoFields(t, resource[1][0])
+	assert.Equal(t, "service.name", string(serviceName[1][0]))
+	assert.Equal(t, "Test", string(protoFields(t, serviceName[2][0])[1][0]))
+	scopeLogs := protoFields(t, resourceLogs[2][0])
+	assert.Equal(t, otlpScopeName, string(protoFields(t, scopeLogs[1][0])[1][0]))
+	record := protoFields(t, scopeLogs[2][0])
+	assert.Equal(t, uint64(1700000000000000000), binary.LittleEndian.Uint64(record[1][0]))
+	assert.Equal(t, uint64(13), binary.LittleEndian.Uint64(record[2][0]))
+	assert.Equal(t, "WARNING", string(record[3][0]))
+	assert.Equal(t, "message", string(protoFields(t, record[5][0])[1][0]))
+	assert.Len(t, record[6], 5)
+	assert.Len(t, record[9][0], 16)
+	assert.Len(t, record[10][0], 8)
+	attempt := protoFields(t, record[6][4])
+	assert.Equal(t, "attempt", string(attempt[1][0]))
+	assert.Equal(t, uint64(2), binary.LittleEndian.Uint64(protoFields(t, attempt[2][0])[3][0]))
+}
+
+func TestOTLPSink_Write_queue(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	sink := NewOTLPSink(OTLPConfig{Endpoint: server.URL, BatchSize: 1, QueueSize: 1, FlushInterval: time.Hour})
+
+	start := time.Now()
+	assert.NoError(t, sink.Write(otlpTestEntry))
+	assert.Eventually(t, func() bool {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		return len(sink.batches) == 0
+	}, time.Second, 5*time.Millisecond)
+	assert.NoError(t, sink.Write(otlpTestEntry))
+	assert.EqualError(t, sink.Write(otlpTestEntry), "otlp: the queue is full, 1 records were dropped")
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	close(release)
+	assert.NoError(t, sink.Close())
+}
+
+func TestLevel_severityNumber(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  int
+	}{
+		{level: LevelTrace, want: 1},
+		{level: LevelDebug, want: 5},
+		{level: LevelInfo, want: 9},
+		{level: LevelNotice, want: 10},
+		{level: LevelWarning, want: 13},
+		{level: LevelError, want: 17},
+		{level: LevelPanic, want: 21},
+		{level: LevelFatal, want: 24},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.level), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.level.severityNumber())
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package logs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// prettyMessageWidth is the width of the messages in FormatPretty, so the fields of the logs are aligned.
+	prettyMessageWidth = 40
+	// prettyIndent is the indentation of the lines after the first one of a log in FormatPretty, below the message.
+	prettyIndent = "                     "
+)
+
+// ANSI escape codes of the colors of FormatPretty.
+const (
+	colorReset = "\x1b[0m"
+	colorDim   = "\x1b[2m"
+	colorKey   = "\x1b[36m"
+)
+
+// levelColors are the ANSI escape codes of the colors of the levels in FormatPretty.
+var levelColors = map[Level]string{
+	LevelTrace:   "\x1b[90m",
+	LevelDebug:   "\x1b[35m",
+	LevelInfo:    "\x1b[32m",
+	LevelNotice:  "\x1b[34m",
+	LevelWarning: "\x1b[33m",
+	LevelError:   "\x1b[31m",
+	LevelPanic:   "\x1b[1;31m",
+	LevelFatal:   "\x1b[1;41;97m",
+}
+
+// stdoutColor reports whether the logs printed in the standard output are colorized.
+var stdoutColor = sync.OnceValue(func() bool {
+	return colorEnabled(os.Stdout)
+})
+
+// colorEnabled reports whether the logs printed in the writer are colorized. They are colorized when the writer is a
+// terminal, unless the environment variable NO_COLOR is set or TERM is dumb.
+func colorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// prettyContent returns the entry as it is printed in the console with FormatPretty, colorized when color is true.
+// Example: 15:04:05.000 ERROR   db down                                  db=users main.go:12:run()
+// The lines after the first one of the message and the stack trace are indented below the message.
+func prettyContent(entry Entry, showDate bool, color bool) string {
+	paint := func(code string, text string) string {
+		if !color || text == "" {
+			return text
+		}
+		return code + text + colorReset
+	}
+	timestamp := entry.Time.Format("15:04:05.000")
+	if showDate {
+		timestamp = entry.Time.Format("2006-01-02 ") + timestamp
+	}
+	lines := strings.Split(strings.TrimSuffix(entry.Message, " "), "\n")
+
+	var builder strings.Builder
+	builder.WriteString(paint(colorDim, timestamp))
+	builder.WriteString(" ")
+	builder.WriteString(paint(levelColors[entry.Level], fmt.Sprintf("%-7s", entry.Level)))
+	builder.WriteString(" ")
+	builder.WriteString(lines[0])
+
+	var fields []string
+	for _, field := range entry.Fields {
+		fields = append(fields, paint(colorKey, field.Key)+"="+fieldValue(field.Value))
+	}
+	caller := ""
+	switch {
+	case entry.File != "":
+		caller = fmt.Sprintf("%s:%d:%s()", entry.File, entry.Line, entry.Function)
+	case entry.Function != "":
+		caller = entry.Function + "()"
+	}
+	if len(fields) > 0 || caller != "" {
+		if padding := prettyMessageWidth - len(lines[0]); padding > 0 {
+			builder.WriteString(strings.Repeat(" ", padding))
+		}
+		if len(fields) > 0 {
+			builder.WriteString(" ")
+			builder.WriteString(strings.Join(fields, " "))
+		}
+		if caller != "" {
+			builder.WriteString(" ")
+			builder.WriteString(paint(colorDim, caller))
+		}
+	}
+	for _, line := range lines[1:] {
+		builder.WriteString("\n" + prettyIndent + line)
+	}
+	for _, frame := range entry.Stack {
+		builder.WriteString("\n" + prettyIndent + paint(colorDim, fmt.Sprintf("at %s()", frame.Function)))
+		builder.WriteString("\n" + prettyIndent + "   " + paint(colorDim, fmt.Sprintf("%s:%d", frame.File, frame.Line)))
+	}
+	return builder.String()
+}
@@ -0,0 +1,98 @@
+package logs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_prettyContent(t *testing.T) {
+	entryTime := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name     string
+		entry    Entry
+		showDate bool
+		color    bool
+		want     string
+	}{
+		{
+			name:  "prettyContent without fields",
+			entry: Entry{Time: entryTime, Level: LevelInfo, Message: "message "},
+			want:  "15:04:05.000 INFO    message",
+		},
+		{
+			name: "prettyContent with fields and caller",
+			entry: Entry{
+				Time: entryTime, Level: LevelError, Message: "db down",
+				File: "main.go", Line: 12, Function: "run",
+				Fields: []Field{Any("db", "users"), Any("query", "select 1")},
+			},
+			showDate: true,
+			want:     `2024-01-02 15:04:05.000 ERROR   db down                                  db=users query="select 1" main.go:12:run()`,
+		},
+		{
+			name: "prettyContent with a multi-line message and a stack trace",
+			entry: Entry{
+				Time: entryTime, Level: LevelWarning, Message: "first\nsecond",
+				Stack: []Frame{{Function: "main.run", File: "/app/main.go", Line: 12}},
+			},
+			want: "15:04:05.000 WARNING first\n" +
+				"                     second\n" +
+				"                     at main.run()\n" +
+				"                        /app/main.go:12",
+		},
+		{
+			name: "prettyContent with colors",
+			entry: Entry{
+				Time: entryTime, Level: LevelError, Message: "db down", Function: "run",
+				Fields: []Field{Any("db", "users")},
+			},
+			color: true,
+			want: "\x1b[2m15:04:05.000\x1b[0m \x1b[31mERROR  \x1b[0m db down" + strings.Repeat(" ", 33) +
+				" \x1b[36mdb\x1b[0m=users \x1b[2mrun()\x1b[0m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, prettyContent(tt.entry, tt.showDate, tt.color))
+		})
+	}
+}
+
+func Test_colorEnabled(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "app.log"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	assert.False(t, colorEnabled(&bytes.Buffer{}))
+	assert.False(t, colorEnabled(file))
+	t.Setenv("NO_COLOR", "1")
+	assert.False(t, colorEnabled(os.Stdout))
+}
+
+func TestConsoleSink_Write_pretty(t *testing.T) {
+	var buffer bytes.Buffer
+	sink := NewConsoleSink(ConsoleConfig{Writer: &buffer, Formatter: Formatter{Format: FormatPretty}})
+	logService := NewService(Service{Sinks: []Sink{sink}})
+
+	logService.With(Any("user", "fsandov")).Info("message")
+
+	assert.Regexp(t, `^\d{2}:\d{2}:\d{2}\.\d{3} INFO    message {33} user=fsandov pretty_test.go:\d+:TestConsoleSink_Write_pretty\(\)\n$`, buffer.String())
+}
+
+func TestService_FormatPretty(t *testing.T) {
+	logService := NewService(Service{NameApp: "api", Format: FormatPretty, FileLog: true, Dir: t.TempDir()})
+
+	logService.With(Any("user", "fsandov")).Info("message")
+
+	content, err := os.ReadFile(logService.fileName)
+	require.NoError(t, err)
+	assert.Equal(t, "[api]-[INFO] message user=fsandov\n", string(content))
+	assert.NotContains(t, string(content), "\x1b")
+}
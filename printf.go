@@ -0,0 +1,265 @@
+package logs
+
+import (
+	"fmt"
+)
+
+// badKey is the key of the values of Infow and the other key and value functions that do not have a string key.
+const badKey = "!BADKEY"
+
+// Tracef is the function that registers the logs with the level Trace. It receives a format and its arguments,
+// like fmt.Printf.
+func (s Service) Tracef(format string, args ...interface{}) {
+	if !s.Enabled(LevelTrace) {
+		return
+	}
+	s.registerOrchestrator(s.logBuilder(LevelTrace, fmt.Sprintf(format, args...)))
+}
+
+// Debugf is the function that registers the logs with the level Debug. It receives a format and its arguments,
+// like fmt.Printf.
+func (s Service) Debugf(format string, args ...interface{}) {
+	if !s.Enabled(LevelDebug) {
+		return
+	}
+	s.registerOrchestrator(s.logBuilder(LevelDebug, fmt.Sprintf(format, args...)))
+}
+
+// Infof is the function that registers the logs with the level Info. It receives a format and its arguments,
+// like fmt.Printf.
+func (s Service) Infof(format string, args ...interface{}) {
+	if !s.Enabled(LevelInfo) {
+		return
+	}
+	s.registerOrchestrator(s.logBuilder(LevelInfo, fmt.Sprintf(format, args...)))
+}
+
+// Noticef is the function that registers the logs with the level Notice. It receives a format and its arguments,
+// like fmt.Printf.
+func (s Service) Noticef(format string, args ...interface{}) {
+	if !s.Enabled(LevelNotice) {
+		return
+	}
+	s.registerOrchestrator(s.logBuilder(LevelNotice, fmt.Sprintf(format, args...)))
+}
+
+// Warningf is the function that registers the logs with the level Warning. It receives a format and its arguments,
+// like fmt.Printf.
+func (s Service) Warningf(format string, args ...interface{}) {
+	if !s.Enabled(LevelWarning) {
+		return
+	}
+	s.registerOrchestrator(s.logBuilder(LevelWarning, fmt.Sprintf(format, args...)))
+}
+
+// Errorf is the function that registers the logs with the level Error. It receives a format and its arguments,
+// like fmt.Printf.
+func (s Service) Errorf(format string, args ...interface{}) {
+	if !s.Enabled(LevelError) {
+		return
+	}
+	s.registerOrchestrator(s.logBuilder(LevelError, fmt.Sprintf(format, args...)))
+}
+
+// Panicf is the function that registers the logs with the level Panic. It receives a format and its arguments,
+// like fmt.Printf. After the log is registered and the sinks are flushed, it panics with the message of the log.
+func (s Service) Panicf(format string, args ...interface{}) {
+	s.terminate(s.logBuilder(LevelPanic, fmt.Sprintf(format, args...)))
+}
+
+// Fatalf is the function that registers the logs with the level Fatal. It receives a format and its arguments,
+// like fmt.Printf. After the log is registered and the sinks are flushed, the application exits with the ExitCode
+// of the service.
+func (s Service) Fatalf(format string, args ...interface{}) {
+	s.terminate(s.logBuilder(LevelFatal, fmt.Sprintf(format, args...)))
+}
+
+// Tracew is the function that registers the logs with the level Trace. It receives the content of the log and
+// alternating keys and values that are attached to the log as fields. Example: service.Tracew("message", "user", 7)
+func (s Service) Tracew(message string, keysAndValues ...interface{}) {
+	if !s.Enabled(LevelTrace) {
+		return
+	}
+	s.registerOrchestrator(s.With(keyValueFields(keysAndValues)...).logBuilder(LevelTrace, message))
+}
+
+// Debugw is the function that registers the logs with the level Debug. It receives the content of the log and
+// alternating keys and values that are attached to the log as fields. Example: service.Debugw("message", "user", 7)
+func (s Service) Debugw(message string, keysAndValues ...interface{}) {
+	if !s.Enabled(LevelDebug) {
+		return
+	}
+	s.registerOrchestrator(s.With(keyValueFields(keysAndValues)...).logBuilder(LevelDebug, message))
+}
+
+// Infow is the function that registers the logs with the level Info. It receives the content of the log and
+// alternating keys and values that are attached to the log as fields. Example: service.Infow("message", "user", 7)
+func (s Service) Infow(message string, keysAndValues ...interface{}) {
+	if !s.Enabled(LevelInfo) {
+		return
+	}
+	s.registerOrchestrator(s.With(keyValueFields(keysAndValues)...).logBuilder(LevelInfo, message))
+}
+
+// Noticew is the function that registers the logs with the level Notice. It receives the content of the log and
+// alternating keys and values that are attached to the log as fields. Example: service.Noticew("message", "user", 7)
+func (s Service) Noticew(message string, keysAndValues ...interface{}) {
+	if !s.Enabled(LevelNotice) {
+		return
+	}
+	s.registerOrchestrator(s.With(keyValueFields(keysAndValues)...).logBuilder(LevelNotice, message))
+}
+
+// Warningw is the function that registers the logs with the level Warning. It receives the content of the log and
+// alternating keys and values that are attached to the log as fields. Example: service.Warningw("message", "user", 7)
+func (s Service) Warningw(message string, keysAndValues ...interface{}) {
+	if !s.Enabled(LevelWarning) {
+		return
+	}
+	s.registerOrchestrator(s.With(keyValueFields(keysAndValues)...).logBuilder(LevelWarning, message))
+}
+
+// Errorw is the function that registers the logs with the level Error. It receives the content of the log and
+// alternating keys and values that are attached to the log as fields. Example: service.Errorw("message", "user", 7)
+func (s Service) Errorw(message string, keysAndValues ...interface{}) {
+	if !s.Enabled(LevelError) {
+		return
+	}
+	s.registerOrchestrator(s.With(keyValueFields(keysAndValues)...).logBuilder(LevelError, message))
+}
+
+// Panicw is the function that registers the logs with the level Panic. It receives the content of the log and
+// alternating keys and values that are attached to the log as fields. After the log is registered and the sinks are
+// flushed, it panics with the message of the log. Example: service.Panicw("message", "user", 7)
+func (s Service) Panicw(message string, keysAndValues ...interface{}) {
+	s.terminate(s.With(keyValueFields(keysAndValues)...).logBuilder(LevelPanic, message))
+}
+
+// Fatalw is the function that registers the logs with the level Fatal. It receives the content of the log and
+// alternating keys and values that are attached to the log as fields. After the log is registered and the sinks are
+// flushed, the application exits with the ExitCode of the service. Example: service.Fatalw("message", "user", 7)
+func (s Service) Fatalw(message string, keysAndValues ...interface{}) {
+	s.terminate(s.With(keyValueFields(keysAndValues)...).logBuilder(LevelFatal, message))
+}
+
+// Tracef is the function that registers the logs with the level Trace. It receives a format and its arguments,
+// like fmt.Printf. The logs will be registered in the DefaultService.
+func Tracef(format string, args ...interface{}) {
+	DefaultService.Tracef(format, args...)
+}
+
+// Debugf is the function that registers the logs with the level Debug. It receives a format and its arguments,
+// like fmt.Printf. The logs will be registered in the DefaultService.
+func Debugf(format string, args ...interface{}) {
+	DefaultService.Debugf(format, args...)
+}
+
+// Infof is the function that registers the logs with the level Info. It receives a format and its arguments,
+// like fmt.Printf. The logs will be registered in the DefaultService.
+func Infof(format string, args ...interface{}) {
+	DefaultService.Infof(format, args...)
+}
+
+// Noticef is the function that registers the logs with the level Notice. It receives a format and its arguments,
+// like fmt.Printf. The logs will be registered in the DefaultService.
+func Noticef(format string, args ...interface{}) {
+	DefaultService.Noticef(format, args...)
+}
+
+// Warningf is the function that registers the logs with the level Warning. It receives a format and its arguments,
+// like fmt.Printf. The logs will be registered in the DefaultService.
+func Warningf(format string, args ...interface{}) {
+	DefaultService.Warningf(format, args...)
+}
+
+// Errorf is the function that registers the logs with the level Error. It receives a format and its arguments,
+// like fmt.Printf. The logs will be registered in the DefaultService.
+func Errorf(format string, args ...interface{}) {
+	DefaultService.Errorf(format, args...)
+}
+
+// Panicf is the function that registers the logs with the level Panic. It receives a format and its arguments,
+// like fmt.Printf. The logs will be registered in the DefaultService. After the log is registered and the sinks are
+// flushed, it panics with the message of the log.
+func Panicf(format string, args ...interface{}) {
+	DefaultService.Panicf(format, args...)
+}
+
+// Fatalf is the function that registers the logs with the level Fatal. It receives a format and its arguments,
+// like fmt.Printf. The logs will be registered in the DefaultService. After the log is registered and the sinks are
+// flushed, the application exits with the ExitCode of the DefaultService.
+func Fatalf(format string, args ...interface{}) {
+	DefaultService.Fatalf(format, args...)
+}
+
+// Tracew is the function that registers the logs with the level Trace. It receives the content of the log and
+// alternating keys and values. The logs will be registered in the DefaultService.
+func Tracew(message string, keysAndValues ...interface{}) {
+	DefaultService.Tracew(message, keysAndValues...)
+}
+
+// Debugw is the function that registers the logs with the level Debug. It receives the content of the log and
+// alternating keys and values. The logs will be registered in the DefaultService.
+func Debugw(message string, keysAndValues ...interface{}) {
+	DefaultService.Debugw(message, keysAndValues...)
+}
+
+// Infow is the function that registers the logs with the level Info. It receives the content of the log and
+// alternating keys and values. The logs will be registered in the DefaultService.
+func Infow(message string, keysAndValues ...interface{}) {
+	DefaultService.Infow(message, keysAndValues...)
+}
+
+// Noticew is the function that registers the logs with the level Notice. It receives the content of the log and
+// alternating keys and values. The logs will be registered in the DefaultService.
+func Noticew(message string, keysAndValues ...interface{}) {
+	DefaultService.Noticew(message, keysAndValues...)
+}
+
+// Warningw is the function that registers the logs with the level Warning. It receives the content of the log and
+// alternating keys and values. The logs will be registered in the DefaultService.
+func Warningw(message string, keysAndValues ...interface{}) {
+	DefaultService.Warningw(message, keysAndValues...)
+}
+
+// Errorw is the function that registers the logs with the level Error. It receives the content of the log and
+// alternating keys and values. The logs will be registered in the DefaultService.
+func Errorw(message string, keysAndValues ...interface{}) {
+	DefaultService.Errorw(message, keysAndValues...)
+}
+
+// Panicw is the function that registers the logs with the level Panic. It receives the content of the log and
+// alternating keys and values. The logs will be registered in the DefaultService. After the log is registered and the
+// sinks are flushed, it panics with the message of the log.
+func Panicw(message string, keysAndValues ...interface{}) {
+	DefaultService.Panicw(message, keysAndValues...)
+}
+
+// Fatalw is the function that registers the logs with the level Fatal. It receives the content of the log and
+// alternating keys and values. The logs will be registered in the DefaultService. After the log is registered and the
+// sinks are flushed, the application exits with the ExitCode of the DefaultService.
+func Fatalw(message string, keysAndValues ...interface{}) {
+	DefaultService.Fatalw(message, keysAndValues...)
+}
+
+// keyValueFields returns the fields of alternating keys and values. A Field is added as it is. The keys that are not
+// strings and a last key without value are added with the key "!BADKEY", like in log/slog.
+func keyValueFields(keysAndValues []interface{}) []Field {
+	var fields []Field
+	for i := 0; i < len(keysAndValues); i++ {
+		switch key := keysAndValues[i].(type) {
+		case Field:
+			fields = append(fields, key)
+		case string:
+			if i == len(keysAndValues)-1 {
+				fields = append(fields, Any(badKey, key))
+				continue
+			}
+			fields = append(fields, Any(key, keysAndValues[i+1]))
+			i++
+		default:
+			fields = append(fields, Any(badKey, key))
+		}
+	}
+	return fields
+}
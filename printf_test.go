@@ -0,0 +1,117 @@
+package logs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_literalMessage(t *testing.T) {
+	sink := &recordSink{}
+	logService := NewService(Service{Sinks: []Sink{sink}})
+	logService.Warning("progress 100%d", "of %s")
+
+	assert.Equal(t, "progress 100%d of %s", sink.entries[0].Message)
+	assert.Contains(t, sink.entries[0].Content, ": progress 100%d of %s")
+}
+
+func TestService_Infof(t *testing.T) {
+	type args struct {
+		format string
+		args   []interface{}
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Infof with arguments",
+			args: args{format: "user %s has %d items", args: []interface{}{"fsandov", 3}},
+			want: "user fsandov has 3 items",
+		},
+		{
+			name: "Infof with a percent sign",
+			args: args{format: "progress %d%%", args: []interface{}{100}},
+			want: "progress 100%",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordSink{}
+			logService := NewService(Service{Sinks: []Sink{sink}})
+			logService.Infof(tt.args.format, tt.args.args...)
+
+			assert.Equal(t, LevelInfo, sink.entries[0].Level)
+			assert.Equal(t, tt.want, sink.entries[0].Message)
+		})
+	}
+}
+
+func TestService_Errorw(t *testing.T) {
+	sink := &recordSink{}
+	logService := NewService(Service{Sinks: []Sink{sink}}).With(Any("app", "api"))
+	logService.Errorw("request failed", "status", 500, Any("path", "/users"))
+
+	entry := sink.entries[0]
+	assert.Equal(t, LevelError, entry.Level)
+	assert.Equal(t, "request failed", entry.Message)
+	assert.Equal(t, "printf_test.go", entry.File)
+	assert.Equal(t, []Field{Any("app", "api"), Any("status", 500), Any("path", "/users")}, entry.Fields)
+	assert.Contains(t, entry.Content, ": request failed app=api status=500 path=/users")
+}
+
+func TestService_terminate(t *testing.T) {
+	sink := &flushSink{}
+	code := -1
+	logService := NewService(Service{Sinks: []Sink{sink}, ExitCode: 2, ExitFunc: func(exitCode int) { code = exitCode }})
+
+	logService.Fatalf("exit %d", 2)
+	assert.Equal(t, 2, code)
+	assert.PanicsWithValue(t, "broken", func() {
+		logService.Panicw("broken", "user", 7)
+	})
+	assert.Equal(t, []Field{Any("user", 7)}, sink.entries[1].Fields)
+	assert.Equal(t, 2, sink.flushed)
+}
+
+func Test_Infof(t *testing.T) {
+	assert.NotPanics(t, func() {
+		Infof("user %s", "fsandov")
+		Infow("message", "user", "fsandov")
+	})
+}
+
+func Test_keyValueFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		keysAndValues []interface{}
+		want          []Field
+	}{
+		{
+			name:          "keyValueFields with pairs",
+			keysAndValues: []interface{}{"user", "fsandov", "id", 7},
+			want:          []Field{Any("user", "fsandov"), Any("id", 7)},
+		},
+		{
+			name:          "keyValueFields with a Field",
+			keysAndValues: []interface{}{Err(nil), "id", 7},
+			want:          []Field{Err(nil), Any("id", 7)},
+		},
+		{
+			name:          "keyValueFields with a key without value",
+			keysAndValues: []interface{}{"id", 7, "user"},
+			want:          []Field{Any("id", 7), Any(badKey, "user")},
+		},
+		{
+			name:          "keyValueFields with a key that is not a string",
+			keysAndValues: []interface{}{7, "id", 8},
+			want:          []Field{Any(badKey, 7), Any("id", 8)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, keyValueFields(tt.keysAndValues))
+		})
+	}
+}
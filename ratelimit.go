@@ -0,0 +1,259 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitOverflow is what the RateLimitSink does with the entries that exceed the rate.
+type RateLimitOverflow string
+
+const (
+	// RateLimitDrop drops the entries that exceed the rate. It is the default overflow.
+	RateLimitDrop RateLimitOverflow = "drop"
+	// RateLimitDigest keeps the entries that exceed the rate and writes them in a single entry, the digest, when a
+	// token is available again.
+	RateLimitDigest RateLimitOverflow = "digest"
+)
+
+// rateLimitDigestSize is the maximum number of entries listed in a digest. The rest are only counted.
+const rateLimitDigestSize = 20
+
+// rateLimitMinInterval is the minimum time between the checks of the digest.
+const rateLimitMinInterval = time.Millisecond
+
+// RateLimitConfig is the struct that contains the configuration of the RateLimitSink. The Sink and the Rate are required.
+type RateLimitConfig struct {
+	// Sink is the sink that receives the entries.
+	Sink Sink
+	// Rate is the number of entries written in every Per. Example: 30
+	Rate int
+	// Per is the time of the Rate. If it is not provided, it will be 1 minute.
+	Per time.Duration
+	// Burst is the number of entries that can be written at once after the sink was idle. If it is not provided, it will be the Rate.
+	Burst int
+	// Overflow is what is done with the entries that exceed the rate. If it is not provided, it will be RateLimitDrop.
+	Overflow RateLimitOverflow
+}
+
+// RateLimitSink is a sink that limits the entries written to another sink with a token bucket. The bucket has Burst
+// tokens and it is refilled with Rate tokens every Per. Every entry takes a token, and the entries without a token
+// are dropped or written later in a digest. It is used to protect a webhook from being banned by the provider.
+// Example: at most 30 messages per minute are sent to Discord.
+// Close should be called when the sink is not used anymore.
+type RateLimitSink struct {
+	config RateLimitConfig
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	// pending are the entries of the next digest, and overflowed is the number of entries of the digest.
+	pending    []Entry
+	overflowed int
+	// dropped is the number of entries dropped.
+	dropped uint64
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewRateLimitSink returns a new RateLimitSink with the configuration provided. With RateLimitDigest, it starts the
+// checks of the digest every time a token is refilled.
+func NewRateLimitSink(config RateLimitConfig) *RateLimitSink {
+	if config.Rate <= 0 {
+		config.Rate = 1
+	}
+	if config.Per <= 0 {
+		config.Per = time.Minute
+	}
+	if config.Burst <= 0 {
+		config.Burst = config.Rate
+	}
+	if config.Overflow == "" {
+		config.Overflow = RateLimitDrop
+	}
+	sink := &RateLimitSink{
+		config: config,
+		tokens: float64(config.Burst),
+		last:   time.Now(),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if config.Overflow == RateLimitDigest {
+		go sink.digestLoop()
+	} else {
+		close(sink.done)
+	}
+	return sink
+}
+
+// Write writes the entry to the sink when a token is available. Otherwise, the entry is dropped or kept for the digest.
+func (r *RateLimitSink) Write(entry Entry) error {
+	r.mu.Lock()
+	if r.overflowed == 0 && r.take(time.Now()) {
+		r.mu.Unlock()
+		return r.config.Sink.Write(entry)
+	}
+	if r.config.Overflow != RateLimitDigest {
+		r.dropped++
+		r.mu.Unlock()
+		metrics.dropped.add(1, entry.NameApp, dropRateLimited)
+		return nil
+	}
+	r.overflowed++
+	metrics.queue.add(1, sinkLimit)
+	if len(r.pending) < rateLimitDigestSize {
+		r.pending = append(r.pending, entry)
+	}
+	digest, ok := r.digest(false)
+	r.mu.Unlock()
+	if ok {
+		return r.config.Sink.Write(digest)
+	}
+	return nil
+}
+
+// Flush writes the digest when a token is available, and flushes the sink when it implements Flusher.
+func (r *RateLimitSink) Flush() error {
+	r.mu.Lock()
+	digest, ok := r.digest(false)
+	r.mu.Unlock()
+	var err error
+	if ok {
+		err = r.config.Sink.Write(digest)
+	}
+	if flusher, isFlusher := r.config.Sink.(Flusher); isFlusher {
+		err = errors.Join(err, flusher.Flush())
+	}
+	return err
+}
+
+// Close stops the checks of the digest, writes the digest even without tokens, and closes the sink when it
+// implements io.Closer.
+func (r *RateLimitSink) Close() error {
+	select {
+	case <-r.stop:
+	default:
+		close(r.stop)
+		<-r.done
+	}
+	r.mu.Lock()
+	digest, ok := r.digest(true)
+	r.mu.Unlock()
+	var err error
+	if ok {
+		err = r.config.Sink.Write(digest)
+	}
+	if closer, isCloser := r.config.Sink.(io.Closer); isCloser {
+		err = errors.Join(err, closer.Close())
+	}
+	return err
+}
+
+// Dropped returns the number of entries dropped by the sink.
+func (r *RateLimitSink) Dropped() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.dropped
+}
+
+// digestLoop writes the digest every time a token is refilled, or every rateLimitMinInterval when the tokens are
+// refilled faster, until the sink is closed.
+func (r *RateLimitSink) digestLoop() {
+	defer close(r.done)
+	interval := r.config.Per / time.Duration(r.config.Rate)
+	if interval < rateLimitMinInterval {
+		interval = rateLimitMinInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.mu.Lock()
+			digest, ok := r.digest(false)
+			r.mu.Unlock()
+			if !ok {
+				continue
+			}
+			if err := r.config.Sink.Write(digest); err != nil {
+				fmt.Println(err)
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// take refills the bucket and takes a token. It returns false when there are no tokens. It must be called with the lock.
+func (r *RateLimitSink) take(now time.Time) bool {
+	elapsed := now.Sub(r.last)
+	r.last = now
+	r.tokens += float64(r.config.Rate) * elapsed.Seconds() / r.config.Per.Seconds()
+	if r.tokens > float64(r.config.Burst) {
+		r.tokens = float64(r.config.Burst)
+	}
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// digest returns the entry that lists the pending entries, and takes a token for it unless force is true.
+// It returns false when there are no pending entries or no tokens. It must be called with the lock.
+// The digest has the highest level of the entries and the content of each one in a line.
+func (r *RateLimitSink) digest(force bool) (Entry, bool) {
+	if r.overflowed == 0 || (!force && !r.take(time.Now())) {
+		return Entry{}, false
+	}
+	level := r.pending[0].Level
+	lines := []string{fmt.Sprintf("%d logs were rate limited:", r.overflowed)}
+	for _, entry := range r.pending {
+		if entry.Level.severity() > level.severity() {
+			level = entry.Level
+		}
+		content, _, _ := strings.Cut(entry.Content, "\n")
+		lines = append(lines, strings.TrimSpace(content))
+	}
+	if more := r.overflowed - len(r.pending); more > 0 {
+		lines = append(lines, fmt.Sprintf("... and %d more", more))
+	}
+	message := strings.Join(lines, "\n")
+	digest := Entry{
+		Time:    time.Now(),
+		Level:   level,
+		NameApp: r.pending[0].NameApp,
+		Message: message,
+		Content: message,
+		Fields:  []Field{Any("rate_limited", r.overflowed)},
+	}
+	metrics.queue.add(-float64(r.overflowed), sinkLimit)
+	r.pending = nil
+	r.overflowed = 0
+	return digest, true
+}
+
+// parseRate returns the rate and the time of a rate like 30/1m. The time can be a unit. Example: 30/m
+func parseRate(value string) (int, time.Duration, error) {
+	count, per, found := strings.Cut(value, "/")
+	rate, err := strconv.Atoi(strings.TrimSpace(count))
+	if !found || err != nil || rate <= 0 {
+		return 0, 0, errors.New("it must be a rate like 30/1m")
+	}
+	per = strings.TrimSpace(per)
+	if per != "" && (per[0] < '0' || per[0] > '9') {
+		per = "1" + per
+	}
+	duration, err := time.ParseDuration(per)
+	if err != nil || duration <= 0 {
+		return 0, 0, errors.New("it must be a rate like 30/1m")
+	}
+	if duration/time.Duration(rate) == 0 {
+		return 0, 0, errors.New("it must be a rate like 30/1m")
+	}
+	return rate, duration, nil
+}
@@ -0,0 +1,148 @@
+package logs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitSink_Write(t *testing.T) {
+	type want struct {
+		written int
+		dropped uint64
+	}
+	tests := []struct {
+		name   string
+		config RateLimitConfig
+		want   want
+	}{
+		{
+			name:   "Write with the burst of the rate",
+			config: RateLimitConfig{Rate: 3},
+			want:   want{written: 3, dropped: 7},
+		},
+		{
+			name:   "Write with a burst",
+			config: RateLimitConfig{Rate: 3, Burst: 5},
+			want:   want{written: 5, dropped: 5},
+		},
+		{
+			name:   "Write with a digest",
+			config: RateLimitConfig{Rate: 3, Overflow: RateLimitDigest},
+			want:   want{written: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordSink{}
+			tt.config.Sink = sink
+			rateLimit := NewRateLimitSink(tt.config)
+			logService := NewService(Service{Sinks: []Sink{rateLimit}})
+
+			for i := 0; i < 10; i++ {
+				logService.Error("db down")
+			}
+
+			assert.Len(t, sink.entries, tt.want.written)
+			assert.Equal(t, tt.want.dropped, rateLimit.Dropped())
+			assert.NoError(t, rateLimit.Close())
+		})
+	}
+}
+
+func TestRateLimitSink_digest(t *testing.T) {
+	sink := &flushSink{}
+	rateLimit := NewRateLimitSink(RateLimitConfig{Sink: sink, Rate: 1, Per: 50 * time.Millisecond, Overflow: RateLimitDigest})
+	defer rateLimit.Close()
+	logService := NewService(Service{NameApp: "api", Sinks: []Sink{rateLimit}})
+
+	logService.Info("first")
+	logService.Info("second")
+	logService.Error("third")
+	assert.Eventually(t, func() bool {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		return len(sink.entries) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	digest := sink.entries[1]
+	assert.Equal(t, LevelError, digest.Level)
+	assert.Equal(t, "2 logs were rate limited:\n[api]-[INFO] second\n[api]-[ERROR] ratelimit_test.go:65:TestRateLimitSink_digest(): third", digest.Message)
+	assert.Equal(t, digest.Message, digest.Content)
+	assert.Equal(t, []Field{Any("rate_limited", 2)}, digest.Fields)
+}
+
+func TestRateLimitSink_Close(t *testing.T) {
+	sink := &recordSink{}
+	rateLimit := NewRateLimitSink(RateLimitConfig{Sink: sink, Rate: 1, Per: time.Hour, Overflow: RateLimitDigest})
+	logService := NewService(Service{Sinks: []Sink{rateLimit}})
+
+	for i := 0; i < 25; i++ {
+		logService.Info("message")
+	}
+	assert.NoError(t, rateLimit.Flush())
+	assert.Len(t, sink.entries, 1)
+	assert.NoError(t, rateLimit.Close())
+
+	assert.Len(t, sink.entries, 2)
+	assert.Contains(t, sink.entries[1].Message, "24 logs were rate limited:")
+	assert.Contains(t, sink.entries[1].Message, "... and 4 more")
+}
+
+func TestNewRateLimitSink_fastRate(t *testing.T) {
+	rateLimit := NewRateLimitSink(RateLimitConfig{Sink: &recordSink{}, Rate: 30, Per: time.Nanosecond, Overflow: RateLimitDigest})
+
+	assert.NoError(t, rateLimit.Write(Entry{Time: time.Now(), Level: LevelInfo, Message: "message"}))
+	assert.NoError(t, rateLimit.Close())
+}
+
+func Test_parseRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		rate    int
+		per     time.Duration
+		wantErr bool
+	}{
+		{name: "parseRate with a duration", value: "30/1m", rate: 30, per: time.Minute},
+		{name: "parseRate with a unit", value: "5/s", rate: 5, per: time.Second},
+		{name: "parseRate without a time", value: "30", wantErr: true},
+		{name: "parseRate with an invalid rate", value: "0/1m", wantErr: true},
+		{name: "parseRate with a rate faster than a nanosecond", value: "30/1ns", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, per, err := parseRate(tt.value)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.rate, rate)
+			assert.Equal(t, tt.per, per)
+		})
+	}
+}
+
+func TestService_URLRateLimit(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	logService := NewService(Service{URL: server.URL, URLRateLimit: &RateLimitConfig{Rate: 2, Per: time.Hour}})
+
+	for i := 0; i < 5; i++ {
+		logService.Errorf("db down %d", i)
+	}
+
+	assert.Equal(t, int32(2), requests.Load())
+}
@@ -0,0 +1,56 @@
+package logs
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// Recover logs the panic of the goroutine, with its value and its stack trace, in the RecoverLevel of the service.
+// It must be called with defer. Example: defer service.Recover()
+// After the log is registered and the sinks are flushed, the panic is swallowed, or it panics again when RePanic is true.
+func (s Service) Recover() {
+	if value := recover(); value != nil {
+		s.recovered(value)
+	}
+}
+
+// Go runs the function in a new goroutine. The panics of the function are logged like in Recover, so a panic in a
+// background goroutine reaches the logs before it stops the application.
+func (s Service) Go(fn func()) {
+	go func() {
+		defer s.Recover()
+		fn()
+	}()
+}
+
+// recovered registers the log of the panic value. The caller of the log is the function that panicked.
+// It is used internally.
+func (s Service) recovered(value interface{}) {
+	level := s.RecoverLevel
+	if level == "" {
+		level = LevelError
+	}
+	message := fmt.Sprintf("panic: %v", value)
+	entry := Entry{
+		Time:    time.Now(),
+		Level:   level,
+		NameApp: s.NameApp,
+		Message: message,
+		Fields:  s.fields,
+		Stack:   stack(),
+	}
+	if err, ok := value.(error); ok {
+		entry.Fields = append(s.fields[:len(s.fields):len(s.fields)], Err(err))
+	}
+	if len(entry.Stack) > 0 {
+		frame := entry.Stack[0]
+		s.callerDecorator(&entry, runtime.Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+	}
+	entry.Content = s.logContent(entry, message)
+	s.registerOrchestrator(entry)
+	s.Flush()
+	if s.RePanic {
+		panic(value)
+	}
+}
@@ -0,0 +1,90 @@
+package logs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Recover(t *testing.T) {
+	type args struct {
+		recoverLevel Level
+		rePanic      bool
+		value        interface{}
+	}
+	type want struct {
+		level   Level
+		message string
+		error   bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "Recover with the default level",
+			args: args{value: "boom"},
+			want: want{level: LevelError, message: "panic: boom"},
+		},
+		{
+			name: "Recover with the level Fatal",
+			args: args{recoverLevel: LevelFatal, value: "boom"},
+			want: want{level: LevelFatal, message: "panic: boom"},
+		},
+		{
+			name: "Recover with an error",
+			args: args{value: errors.New("db down")},
+			want: want{level: LevelError, message: "panic: db down", error: true},
+		},
+		{
+			name: "Recover with RePanic",
+			args: args{rePanic: true, value: "boom"},
+			want: want{level: LevelError, message: "panic: boom"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &flushSink{}
+			logService := NewService(Service{Sinks: []Sink{sink}, RecoverLevel: tt.args.recoverLevel, RePanic: tt.args.rePanic})
+			run := func() {
+				defer logService.Recover()
+				panic(tt.args.value)
+			}
+
+			if tt.args.rePanic {
+				assert.PanicsWithValue(t, tt.args.value, run)
+			} else {
+				assert.NotPanics(t, run)
+			}
+			entry := sink.entries[0]
+			assert.Equal(t, tt.want.level, entry.Level)
+			assert.Equal(t, tt.want.message, entry.Message)
+			assert.Equal(t, "recover_test.go", entry.File)
+			assert.Equal(t, "1", entry.Function)
+			assert.Equal(t, "github.com/fsandov/logs.TestService_Recover.func1.1", entry.Stack[0].Function)
+			assert.True(t, strings.Contains(entry.Content, "panic: "))
+			assert.Equal(t, tt.want.error, len(entry.Fields) > 0 && entry.Fields[len(entry.Fields)-1].Key == ErrorField)
+			assert.Equal(t, 1, sink.flushed)
+		})
+	}
+}
+
+func TestService_Go(t *testing.T) {
+	sink := &flushSink{}
+	logService := NewService(Service{Sinks: []Sink{sink}})
+	logService.Go(func() {
+		panic("boom")
+	})
+
+	assert.Eventually(t, func() bool {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		return sink.flushed == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "panic: boom", sink.entries[0].Message)
+	assert.Equal(t, "github.com/fsandov/logs.TestService_Go.func1", sink.entries[0].Stack[0].Function)
+}
@@ -0,0 +1,210 @@
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ConfigWatcherConfig is the struct that contains the configuration of the ConfigWatcher. Only the Path is required.
+type ConfigWatcherConfig struct {
+	// Path is the path of the YAML or JSON configuration file, in the format of LoadConfig.
+	Path string
+	// Interval is the time between the checks of the file. If it is not provided, it will be 5 seconds.
+	Interval time.Duration
+}
+
+// ConfigWatcher keeps a Service configured with a configuration file, and applies the changes of the file while the
+// application is running. The file is checked every Interval. The level and the sinks of the file are changed
+// atomically in all the copies of the service, and the logs registered during the change reach the previous or the
+// new sinks. The app, the formats, the caller format, the stack level and the redaction of the service are taken only
+// from the first version of the file, and the formats of the sinks are changed with the sinks.
+type ConfigWatcher struct {
+	config  ConfigWatcherConfig
+	service Service
+	sinks   *reloadSink
+	mu      sync.Mutex
+	data    []byte
+	failure string
+	level   Level
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewConfigWatcher returns a new ConfigWatcher with the configuration provided. It loads the file and starts the checks
+// of the file. It returns an error when the first version of the file is not valid.
+func NewConfigWatcher(config ConfigWatcherConfig) (*ConfigWatcher, error) {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Second
+	}
+	data, err := os.ReadFile(config.Path)
+	if err != nil {
+		return nil, fmt.Errorf("logs: %w", err)
+	}
+	file, err := readConfig(config.Path, data)
+	if err != nil {
+		return nil, err
+	}
+	sinks := &reloadSink{sinks: file.sinks()}
+	service := file.service([]Sink{sinks})
+	w := &ConfigWatcher{
+		config:  config,
+		service: service,
+		sinks:   sinks,
+		data:    data,
+		level:   file.level(),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.watch()
+	return w, nil
+}
+
+// Service returns the service configured with the file.
+func (w *ConfigWatcher) Service() Service {
+	return w.service
+}
+
+// Reload applies the configuration file when it changed since the last time it was applied. The result is registered
+// in the logs of the service. When the file is not valid, the previous configuration is kept, and the error is
+// registered only when it is different from the previous one.
+func (w *ConfigWatcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	data, err := os.ReadFile(w.config.Path)
+	if err != nil {
+		err = fmt.Errorf("logs: %w", err)
+		w.fail(err)
+		return err
+	}
+	if bytes.Equal(data, w.data) {
+		w.failure = ""
+		return nil
+	}
+	file, err := readConfig(w.config.Path, data)
+	if err != nil {
+		w.fail(err)
+		return err
+	}
+	w.data = data
+	w.failure = ""
+	sinks := file.sinks()
+	if err := closeSinks(w.sinks.swap(sinks)); err != nil {
+		w.log(LevelError, "the previous sinks could not be closed", Err(err))
+	}
+	if level := file.level(); level != w.level {
+		w.level = level
+		w.service.SetLevel(level)
+	}
+	w.log(LevelInfo, "the configuration was reloaded", Any("level", w.level), Any("sinks", len(sinks)))
+	return nil
+}
+
+// fail registers the error of a reload, unless it is the same error of the previous reload.
+func (w *ConfigWatcher) fail(err error) {
+	if err.Error() == w.failure {
+		return
+	}
+	w.failure = err.Error()
+	w.log(LevelError, "the configuration could not be reloaded", Err(err))
+}
+
+// log registers a log of the watcher with the path of the file. The logs have no caller, because they are registered
+// by the watcher and not by the application.
+func (w *ConfigWatcher) log(level Level, message string, fields ...Field) {
+	service := w.service.With(append([]Field{Any("path", w.config.Path)}, fields...)...)
+	service.registerOrchestrator(service.internalEntry(level, message))
+}
+
+// Close stops the checks of the file, and flushes and closes the sinks.
+func (w *ConfigWatcher) Close() error {
+	select {
+	case <-w.stop:
+	default:
+		close(w.stop)
+	}
+	<-w.done
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return closeSinks(w.sinks.swap(nil))
+}
+
+// watch checks the file every Interval until the watcher is closed.
+func (w *ConfigWatcher) watch() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			_ = w.Reload()
+		}
+	}
+}
+
+// reloadSink is a sink that writes to a list of sinks that can be replaced while the entries are written.
+type reloadSink struct {
+	mu    sync.RWMutex
+	sinks []Sink
+}
+
+// Write writes the entry to all the sinks.
+func (r *reloadSink) Write(entry Entry) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var errs []error
+	for _, sink := range r.sinks {
+		if err := sink.Write(entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Flush flushes the sinks that implement Flusher.
+func (r *reloadSink) Flush() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var errs []error
+	for _, sink := range r.sinks {
+		if flusher, ok := sink.(Flusher); ok {
+			if err := flusher.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// swap replaces the sinks and returns the previous ones. The entries that are being written finish before.
+func (r *reloadSink) swap(sinks []Sink) []Sink {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	previous := r.sinks
+	r.sinks = sinks
+	return previous
+}
+
+// closeSinks flushes the sinks that implement Flusher and closes the sinks that implement io.Closer.
+func closeSinks(sinks []Sink) error {
+	var errs []error
+	for _, sink := range sinks {
+		if flusher, ok := sink.(Flusher); ok {
+			if err := flusher.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if closer, ok := sink.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
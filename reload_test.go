@@ -0,0 +1,72 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigWatcher_Reload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs.yml")
+	first := filepath.Join(dir, "first.log")
+	second := filepath.Join(dir, "second.log")
+	writeConfig := func(level string, logPath string) {
+		content := "app: api\nlevel: " + level + "\nsinks:\n  - type: file\n    path: " + logPath + "\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	writeConfig("info", first)
+	watcher, err := NewConfigWatcher(ConfigWatcherConfig{Path: path, Interval: time.Hour})
+	require.NoError(t, err)
+	service := watcher.Service().With(Any("user", "fsandov"))
+
+	service.Debug("dropped")
+	service.Info("first")
+	writeConfig("debug", second)
+	require.NoError(t, watcher.Reload())
+	service.Debug("second")
+	require.NoError(t, os.WriteFile(path, []byte("level: verbose\n"), 0644))
+	assert.EqualError(t, watcher.Reload(), `logs: `+path+`:1: level: unknown level "verbose"`)
+	assert.Error(t, watcher.Reload())
+	require.NoError(t, os.WriteFile(path, []byte("level: trace\nsinks: []\nunknown: true\n"), 0644))
+	assert.Error(t, watcher.Reload())
+	require.NoError(t, watcher.Close())
+
+	content, err := os.ReadFile(first)
+	require.NoError(t, err)
+	assert.Equal(t, "[api]-[INFO] first user=fsandov\n", string(content))
+	content, err = os.ReadFile(second)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "[api]-[INFO] the configuration was reloaded path="+path+" level=DEBUG sinks=1", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "[api]-[DEBUG] reload_test.go:"))
+	assert.True(t, strings.HasSuffix(lines[1], ":TestConfigWatcher_Reload(): second user=fsandov"))
+	assert.True(t, strings.HasPrefix(lines[2], "[api]-[ERROR] the configuration could not be reloaded path="+path+" error="))
+	assert.Contains(t, lines[2], `unknown level \"verbose\"`)
+	assert.True(t, strings.HasPrefix(lines[3], "[api]-[ERROR] the configuration could not be reloaded path="+path+" error="))
+	assert.Contains(t, lines[3], "unknown")
+	assert.Equal(t, LevelDebug, service.CurrentLevel())
+}
+
+func TestConfigWatcher_watch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs.yml")
+	require.NoError(t, os.WriteFile(path, []byte("level: info\n"), 0644))
+	watcher, err := NewConfigWatcher(ConfigWatcherConfig{Path: path, Interval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	require.NoError(t, os.WriteFile(path, []byte("level: error\n"), 0644))
+	assert.Eventually(t, func() bool { return watcher.Service().CurrentLevel() == LevelError }, time.Second, 10*time.Millisecond)
+}
+
+func TestNewConfigWatcher(t *testing.T) {
+	_, err := NewConfigWatcher(ConfigWatcherConfig{Path: filepath.Join(t.TempDir(), "missing.yml")})
+	assert.Error(t, err)
+}
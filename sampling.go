@@ -0,0 +1,167 @@
+package logs
+
+import (
+	"hash/fnv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// samplingMessage is the message of the logs that report the entries dropped by the sampling.
+const samplingMessage = "logs were dropped by the sampling"
+
+// samplingBuckets is the number of counters of each level. The messages share a counter when their hashes collide,
+// so the memory of the sampling is bounded.
+const samplingBuckets = 4096
+
+// SamplingConfig is the struct that contains the configuration of the sampling of the logs. The logs are sampled by
+// level and message: in every Interval, the first Initial logs of the same level and message are registered, and then
+// only one of every Thereafter logs. The levels from Warning are not sampled unless they are listed in Levels, and the
+// levels Panic and Fatal are never sampled.
+type SamplingConfig struct {
+	// Interval is the time in which the logs are counted. If it is not provided, it will be 1 second.
+	Interval time.Duration
+	// Initial is the number of logs of the same level and message registered in every Interval.
+	// If it is not provided, it will be 100.
+	Initial int
+	// Thereafter is the number of logs after the Initial ones for each one that is registered.
+	// If it is not provided, it will be 100.
+	Thereafter int
+	// Levels are the configurations of the levels that are sampled in a different way. The values that are not provided
+	// are taken from the configuration. Example: {LevelDebug: {Initial: 10}, LevelWarning: {Initial: 1000}}
+	Levels map[Level]SamplingLevel
+	// ReportInterval is the time between the logs with the Warning level that report the number of logs dropped by
+	// the sampling. They are also reported by Flush. If it is not provided, it will be 1 minute.
+	ReportInterval time.Duration
+}
+
+// SamplingLevel is the struct that contains the configuration of the sampling of a level.
+type SamplingLevel struct {
+	// Initial is the number of logs of the same message registered in every Interval.
+	Initial int
+	// Thereafter is the number of logs after the Initial ones for each one that is registered.
+	Thereafter int
+	// Disabled is true to register all the logs of the level.
+	Disabled bool
+}
+
+// sampler keeps the counters of the sampling of a service. It is shared by all the copies of the service.
+type sampler struct {
+	interval       time.Duration
+	reportInterval time.Duration
+	// levels are the counters of the sampled levels.
+	levels map[Level]*samplingLevel
+	// reportAt is the time, in nanoseconds, of the next report of the dropped logs.
+	reportAt atomic.Int64
+}
+
+// samplingLevel keeps the counters of the sampling of a level.
+type samplingLevel struct {
+	initial    uint64
+	thereafter uint64
+	counters   [samplingBuckets]samplingCounter
+	dropped    atomic.Uint64
+}
+
+// samplingCounter counts the logs of a message in the current interval.
+type samplingCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// newSampler returns the sampler of the configuration provided. It returns nil when the configuration is not provided.
+func newSampler(config *SamplingConfig) *sampler {
+	if config == nil {
+		return nil
+	}
+	s := &sampler{
+		interval:       config.Interval,
+		reportInterval: config.ReportInterval,
+		levels:         map[Level]*samplingLevel{},
+	}
+	if s.interval <= 0 {
+		s.interval = time.Second
+	}
+	if s.reportInterval <= 0 {
+		s.reportInterval = time.Minute
+	}
+	for _, level := range []Level{LevelTrace, LevelDebug, LevelInfo, LevelNotice, LevelWarning, LevelError} {
+		levelConfig, ok := config.Levels[level]
+		if levelConfig.Disabled || (!ok && level.severity() >= LevelWarning.severity()) {
+			continue
+		}
+		initial, thereafter := levelConfig.Initial, levelConfig.Thereafter
+		if initial <= 0 {
+			initial = config.Initial
+		}
+		if thereafter <= 0 {
+			thereafter = config.Thereafter
+		}
+		if initial <= 0 {
+			initial = 100
+		}
+		if thereafter <= 0 {
+			thereafter = 100
+		}
+		s.levels[level] = &samplingLevel{initial: uint64(initial), thereafter: uint64(thereafter)}
+	}
+	s.reportAt.Store(time.Now().Add(s.reportInterval).UnixNano())
+	return s
+}
+
+// sample reports whether the entry is registered. The entries that are not registered are counted to be reported.
+func (s *sampler) sample(entry Entry) bool {
+	level, ok := s.levels[entry.Level]
+	if !ok {
+		return true
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(entry.Message))
+	count := level.counters[hash.Sum32()%samplingBuckets].increment(entry.Time, s.interval)
+	if count <= level.initial || (count-level.initial)%level.thereafter == 0 {
+		return true
+	}
+	level.dropped.Add(1)
+	return false
+}
+
+// increment adds a log to the counter and returns the number of logs in the current interval. The counter starts
+// again when the interval ends.
+func (c *samplingCounter) increment(now time.Time, interval time.Duration) uint64 {
+	nanos := now.UnixNano()
+	resetAt := c.resetAt.Load()
+	if resetAt > nanos {
+		return c.count.Add(1)
+	}
+	c.count.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, nanos+interval.Nanoseconds()) {
+		return c.count.Add(1)
+	}
+	return 1
+}
+
+// report returns the fields of the log that reports the logs dropped since the last report. It returns false when
+// the ReportInterval did not end, unless force is true, or when no logs were dropped.
+func (s *sampler) report(now time.Time, force bool) ([]Field, bool) {
+	reportAt := s.reportAt.Load()
+	if !force && now.UnixNano() < reportAt {
+		return nil, false
+	}
+	if !s.reportAt.CompareAndSwap(reportAt, now.Add(s.reportInterval).UnixNano()) {
+		return nil, false
+	}
+	var fields []Field
+	var total uint64
+	for _, level := range []Level{LevelTrace, LevelDebug, LevelInfo, LevelNotice, LevelWarning, LevelError} {
+		if counters, ok := s.levels[level]; ok {
+			if dropped := counters.dropped.Swap(0); dropped > 0 {
+				total += dropped
+				fields = append(fields, Any("dropped_"+strings.ToLower(string(level)), dropped))
+			}
+		}
+	}
+	if total == 0 {
+		return nil, false
+	}
+	return append([]Field{Any("dropped", total)}, fields...), true
+}
@@ -0,0 +1,122 @@
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Sampling(t *testing.T) {
+	type want struct {
+		registered int
+	}
+	tests := []struct {
+		name     string
+		sampling *SamplingConfig
+		level    Level
+		want     want
+	}{
+		{
+			name:  "Without sampling",
+			level: LevelDebug,
+			want:  want{registered: 20},
+		},
+		{
+			name:     "First logs and then one of every Thereafter",
+			sampling: &SamplingConfig{Initial: 5, Thereafter: 5},
+			level:    LevelDebug,
+			want:     want{registered: 8},
+		},
+		{
+			name:     "Configuration of the level",
+			sampling: &SamplingConfig{Initial: 5, Thereafter: 5, Levels: map[Level]SamplingLevel{LevelInfo: {Initial: 2}}},
+			level:    LevelInfo,
+			want:     want{registered: 5},
+		},
+		{
+			name:     "Level disabled",
+			sampling: &SamplingConfig{Initial: 5, Thereafter: 5, Levels: map[Level]SamplingLevel{LevelInfo: {Disabled: true}}},
+			level:    LevelInfo,
+			want:     want{registered: 20},
+		},
+		{
+			name:     "Warning is not sampled by default",
+			sampling: &SamplingConfig{Initial: 5, Thereafter: 5},
+			level:    LevelWarning,
+			want:     want{registered: 20},
+		},
+		{
+			name:     "Warning is sampled when it is configured",
+			sampling: &SamplingConfig{Initial: 5, Thereafter: 5, Levels: map[Level]SamplingLevel{LevelWarning: {}}},
+			level:    LevelWarning,
+			want:     want{registered: 8},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordSink{}
+			logService := NewService(Service{Sampling: tt.sampling, Sinks: []Sink{sink}})
+
+			for i := 0; i < 20; i++ {
+				logService.registerOrchestrator(logService.logBuilder(tt.level, "hot loop"))
+			}
+
+			assert.Len(t, sink.entries, tt.want.registered)
+		})
+	}
+}
+
+func TestService_Sampling_messages(t *testing.T) {
+	sink := &recordSink{}
+	logService := NewService(Service{Sampling: &SamplingConfig{Initial: 1, Thereafter: 100}, Sinks: []Sink{sink}})
+
+	for i := 0; i < 3; i++ {
+		logService.Debug("first")
+		logService.Debug("second")
+		logService.Info("first")
+	}
+
+	assert.Len(t, sink.entries, 3)
+}
+
+func TestService_Sampling_interval(t *testing.T) {
+	sink := &recordSink{}
+	logService := NewService(Service{Sampling: &SamplingConfig{Interval: 50 * time.Millisecond, Initial: 1, Thereafter: 100}, Sinks: []Sink{sink}})
+
+	logService.Debug("hot loop")
+	logService.Debug("hot loop")
+	time.Sleep(60 * time.Millisecond)
+	logService.Debug("hot loop")
+
+	assert.Len(t, sink.entries, 2)
+}
+
+func TestService_Sampling_report(t *testing.T) {
+	sink := &recordSink{}
+	logService := NewService(Service{
+		Sampling: &SamplingConfig{Initial: 1, Thereafter: 100, ReportInterval: 50 * time.Millisecond},
+		Sinks:    []Sink{sink},
+	})
+
+	for i := 0; i < 4; i++ {
+		logService.Debug("hot loop")
+		logService.Info("hot loop")
+	}
+	time.Sleep(60 * time.Millisecond)
+	logService.Debug("hot loop")
+
+	if assert.Len(t, sink.entries, 3) {
+		report := sink.entries[2]
+		assert.Equal(t, LevelWarning, report.Level)
+		assert.Equal(t, samplingMessage, report.Message)
+		assert.Empty(t, report.File)
+		assert.Equal(t, []Field{Any("dropped", uint64(6)), Any("dropped_debug", uint64(3)), Any("dropped_info", uint64(3))}, report.Fields)
+	}
+
+	logService.Flush()
+	assert.Len(t, sink.entries, 4)
+	assert.Equal(t, []Field{Any("dropped", uint64(1)), Any("dropped_debug", uint64(1))}, sink.entries[3].Fields)
+	logService.Flush()
+	assert.Len(t, sink.entries, 4)
+}
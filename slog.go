@@ -0,0 +1,130 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+)
+
+const (
+	// SlogLevelTrace is the slog level that is registered with the level Trace.
+	SlogLevelTrace = slog.LevelDebug - 4
+	// SlogLevelNotice is the slog level that is registered with the level Notice.
+	SlogLevelNotice = slog.LevelInfo + 2
+	// SlogLevelPanic is the slog level that is registered with the level Panic. The handler does not panic.
+	SlogLevelPanic = slog.LevelError + 2
+	// SlogLevelFatal is the slog level that is registered with the level Fatal. The handler does not exit.
+	SlogLevelFatal = slog.LevelError + 4
+)
+
+// Handler is a slog.Handler that registers the records in a Service, so they reach the same outputs and sinks.
+// The attributes of the records are attached to the logs as fields. The attributes inside groups are
+// attached with the names of the groups as prefix, separated by dots. Example: request.method
+type Handler struct {
+	service Service
+	group   string
+}
+
+// NewHandler returns a new Handler that registers the records in the service provided.
+// Example: slog.New(logs.NewHandler(service))
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Enabled reports whether the handler registers the records of the level provided, with the minimum level of the service.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.service.Enabled(slogLevel(level))
+}
+
+// Handle registers the record in the service. The caller is taken from the PC of the record, and the trace and
+// request IDs of the context are attached like with Service.WithContext.
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	if !h.service.Enabled(slogLevel(record.Level)) {
+		return nil
+	}
+	service := h.service
+	if ctx != nil {
+		service = service.WithContext(ctx)
+	}
+	fields := service.fields[:len(service.fields):len(service.fields)]
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttr(fields, h.group, attr)
+		return true
+	})
+	entry := Entry{
+		Time:    record.Time,
+		Level:   slogLevel(record.Level),
+		NameApp: service.NameApp,
+		Message: record.Message,
+		Fields:  fields,
+	}
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		service.callerDecorator(&entry, frame)
+	}
+	service.stackDecorator(&entry)
+	entry.Content = service.logContent(entry, entry.Message)
+	service.registerOrchestrator(entry)
+	return nil
+}
+
+// WithAttrs returns a new Handler that attaches the attributes provided to all of its records.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	var fields []Field
+	for _, attr := range attrs {
+		fields = appendAttr(fields, h.group, attr)
+	}
+	return &Handler{service: h.service.With(fields...), group: h.group}
+}
+
+// WithGroup returns a new Handler that attaches the attributes of its records inside the group provided.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &Handler{service: h.service, group: h.group + name + "."}
+}
+
+// appendAttr appends the attribute to the fields with the prefix of its groups. The empty attributes are ignored
+// and the groups without a name are inlined, as the slog.Handler documentation requires.
+func appendAttr(fields []Field, prefix string, attr slog.Attr) []Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, groupAttr := range attr.Value.Group() {
+			fields = appendAttr(fields, prefix, groupAttr)
+		}
+		return fields
+	}
+	return append(fields, Field{Key: prefix + attr.Key, Value: attr.Value.Any()})
+}
+
+// slogLevel returns the level of the logs that corresponds to the slog level.
+func slogLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelDebug:
+		return LevelTrace
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < SlogLevelNotice:
+		return LevelInfo
+	case level < slog.LevelWarn:
+		return LevelNotice
+	case level < slog.LevelError:
+		return LevelWarning
+	case level < SlogLevelPanic:
+		return LevelError
+	case level < SlogLevelFatal:
+		return LevelPanic
+	default:
+		return LevelFatal
+	}
+}
@@ -0,0 +1,90 @@
+package logs
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordSink is a sink that keeps the entries it receives. It is used in the tests.
+type recordSink struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func (r *recordSink) Write(entry Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func TestHandler_slogtest(t *testing.T) {
+	sink := &recordSink{}
+	handler := NewHandler(NewService(Service{Sinks: []Sink{sink}}))
+
+	err := slogtest.TestHandler(handler, func() []map[string]any {
+		var results []map[string]any
+		for _, entry := range sink.entries {
+			result := map[string]any{slog.LevelKey: entry.Level, slog.MessageKey: entry.Message}
+			if !entry.Time.IsZero() {
+				result[slog.TimeKey] = entry.Time
+			}
+			for _, field := range entry.Fields {
+				group := result
+				keys := strings.Split(field.Key, ".")
+				for _, key := range keys[:len(keys)-1] {
+					if _, ok := group[key].(map[string]any); !ok {
+						group[key] = map[string]any{}
+					}
+					group = group[key].(map[string]any)
+				}
+				group[keys[len(keys)-1]] = field.Value
+			}
+			results = append(results, result)
+		}
+		return results
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	sink := &recordSink{}
+	logger := slog.New(NewHandler(NewService(Service{NameApp: "Test", Sinks: []Sink{sink}})))
+	logger.With("user", "fsandov").WithGroup("request").Warn("message", "method", "GET", slog.Group("route", "id", 1))
+
+	entry := sink.entries[0]
+	assert.Equal(t, LevelWarning, entry.Level)
+	assert.Equal(t, "message", entry.Message)
+	assert.Equal(t, "slog_test.go", entry.File)
+	assert.Equal(t, "TestHandler_Handle", entry.Function)
+	assert.Equal(t, []Field{Any("user", "fsandov"), Any("request.method", "GET"), Any("request.route.id", int64(1))}, entry.Fields)
+	assert.True(t, strings.HasPrefix(entry.Content, "[Test]-[WARNING] slog_test.go:"))
+	assert.True(t, strings.HasSuffix(entry.Content, ":TestHandler_Handle(): message user=fsandov request.method=GET request.route.id=1"))
+}
+
+func Test_slogLevel(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  Level
+	}{
+		{level: SlogLevelTrace, want: LevelTrace},
+		{level: slog.LevelDebug, want: LevelDebug},
+		{level: slog.LevelInfo, want: LevelInfo},
+		{level: SlogLevelNotice, want: LevelNotice},
+		{level: slog.LevelWarn, want: LevelWarning},
+		{level: slog.LevelError, want: LevelError},
+		{level: SlogLevelPanic, want: LevelPanic},
+		{level: SlogLevelFatal, want: LevelFatal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, slogLevel(tt.level))
+		})
+	}
+}
@@ -0,0 +1,386 @@
+package logs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	splunkEventPath = "/services/collector/event"
+	splunkAckPath   = "/services/collector/ack"
+	// splunkCodeInternalError and splunkCodeServerBusy are the HEC codes that can be retried.
+	splunkCodeInternalError = 8
+	splunkCodeServerBusy    = 9
+)
+
+// SplunkConfig is the struct that contains the configuration of the SplunkSink.
+// Only the URL and the Token are required.
+type SplunkConfig struct {
+	// URL is the base URL of the HTTP Event Collector. Example: https://splunk.example.com:8088
+	// The events will be posted to the path /services/collector/event.
+	URL string
+	// Token is the token of the HTTP Event Collector.
+	Token string
+	// Index is the index where the events will be stored. If it is not provided, the default index of the token is used.
+	Index string
+	// SourceType is the sourcetype of the events. If it is not provided, it will be "_json".
+	SourceType string
+	// Source is the source of the events. If it is not provided, it will be the name of the application of the entry.
+	Source string
+	// Host is the host of the events. If it is not provided, it will be the hostname of the machine.
+	Host string
+	// BatchSize is the number of events sent in a single request. If it is not provided, it will be 50.
+	BatchSize int
+	// FlushInterval is the maximum time an event waits in the batch before it is sent. If it is not provided, it will be 5 seconds.
+	FlushInterval time.Duration
+	// Ack is a boolean that indicates if the sink should wait for the indexer acknowledgement of every batch.
+	// The acknowledgement must be enabled in the token of the HTTP Event Collector.
+	Ack bool
+	// Channel is the channel used for the acknowledgement. If it is not provided and Ack is true, a random one is generated.
+	Channel string
+	// AckTimeout is the maximum time to wait for the acknowledgement of a batch. If it is not provided, it will be 30 seconds.
+	AckTimeout time.Duration
+	// MaxRetries is the number of times a batch is sent again when the server is busy. If it is not provided, it will be 3.
+	MaxRetries int
+	// QueueSize is the number of full batches that wait to be sent in the background. When the queue is full, the new
+	// full batches are dropped and Write returns an error. If it is not provided, it will be 10.
+	QueueSize int
+	// Client is the HTTP client used to send the events. If it is not provided, http.DefaultClient is used.
+	Client *http.Client
+}
+
+// SplunkSink is a sink that sends the logs to a Splunk HTTP Event Collector. The events are sent in batches in the
+// background, so Flush or Close should be called before the application stops.
+type SplunkSink struct {
+	config  SplunkConfig
+	mu      sync.Mutex
+	events  [][]byte
+	batches [][][]byte
+	queued  chan struct{}
+	sendMu  sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// splunkEvent is the event in the format accepted by the HTTP Event Collector.
+type splunkEvent struct {
+	Time       float64         `json:"time"`
+	Host       string          `json:"host,omitempty"`
+	Source     string          `json:"source,omitempty"`
+	SourceType string          `json:"sourcetype,omitempty"`
+	Index      string          `json:"index,omitempty"`
+	Event      splunkEventData `json:"event"`
+}
+
+// splunkEventData is the content of the event.
+type splunkEventData struct {
+	Level    Level                  `json:"level"`
+	App      string                 `json:"app"`
+	Message  string                 `json:"message"`
+	File     string                 `json:"file,omitempty"`
+	Line     int                    `json:"line,omitempty"`
+	Function string                 `json:"function,omitempty"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+	Stack    []Frame                `json:"stack,omitempty"`
+}
+
+// splunkResponse is the response of the HTTP Event Collector.
+type splunkResponse struct {
+	Text  string `json:"text"`
+	Code  int    `json:"code"`
+	AckID *int64 `json:"ackId"`
+}
+
+// SplunkError is the error returned by the HTTP Event Collector. Code is the HEC error code, like 4 for an invalid token.
+type SplunkError struct {
+	StatusCode int
+	Code       int
+	Text       string
+}
+
+// Error returns the message of the error.
+func (e *SplunkError) Error() string {
+	return fmt.Sprintf("splunk: %s (code %d, status %d)", e.Text, e.Code, e.StatusCode)
+}
+
+// retryable returns true when the batch can be sent again.
+func (e *SplunkError) retryable() bool {
+	return e.Code == splunkCodeInternalError || e.Code == splunkCodeServerBusy || e.StatusCode == http.StatusServiceUnavailable
+}
+
+// NewSplunkSink returns a new SplunkSink with the configuration provided. It starts the flush of the batches
+// every FlushInterval.
+func NewSplunkSink(config SplunkConfig) *SplunkSink {
+	config.URL = strings.TrimSuffix(config.URL, "/")
+	if config.SourceType == "" {
+		config.SourceType = "_json"
+	}
+	if config.Host == "" {
+		config.Host, _ = os.Hostname()
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 50
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 5 * time.Second
+	}
+	if config.Ack && config.Channel == "" {
+		config.Channel = newChannel()
+	}
+	if config.AckTimeout <= 0 {
+		config.AckTimeout = 30 * time.Second
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = 3
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 10
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	sink := &SplunkSink{
+		config: config,
+		queued: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go sink.flushLoop()
+	return sink
+}
+
+// Write adds the entry to the batch. When the batch reaches the BatchSize, it is queued to be sent in the background,
+// so Write does not wait for the HTTP Event Collector. It returns an error when the queue is full and the batch is
+// dropped.
+func (s *SplunkSink) Write(entry Entry) error {
+	source := s.config.Source
+	if source == "" {
+		source = entry.NameApp
+	}
+	event, err := json.Marshal(splunkEvent{
+		Time:       float64(entry.Time.UnixMilli()) / 1000,
+		Host:       s.config.Host,
+		Source:     source,
+		SourceType: s.config.SourceType,
+		Index:      s.config.Index,
+		Event: splunkEventData{
+			Level:    entry.Level,
+			App:      entry.NameApp,
+			Message:  entry.Message,
+			File:     entry.File,
+			Line:     entry.Line,
+			Function: entry.Function,
+			Fields:   fieldsMap(entry.Fields),
+			Stack:    entry.Stack,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("splunk: error marshaling event: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	metrics.queue.add(1, sinkSplunk)
+	if len(s.events) < s.config.BatchSize {
+		return nil
+	}
+	events := s.events
+	s.events = nil
+	if len(s.batches) >= s.config.QueueSize {
+		metrics.queue.add(-float64(len(events)), sinkSplunk)
+		metrics.dropped.add(float64(len(events)), entry.NameApp, dropQueueFull)
+		return fmt.Errorf("splunk: the queue is full, %d events were dropped", len(events))
+	}
+	s.batches = append(s.batches, events)
+	select {
+	case s.queued <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Flush sends the queued batches and the events of the current batch. It waits for the batch that is being sent in
+// the background. If Ack is true, it waits for the acknowledgement of the indexer.
+func (s *SplunkSink) Flush() error {
+	return s.sendBatches(true)
+}
+
+// sendBatches sends the queued batches, and the current batch when current is true. The batches are taken and sent
+// while sendMu is locked, so a call waits for the batches that are being sent by another one.
+func (s *SplunkSink) sendBatches(current bool) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	s.mu.Lock()
+	batches := s.batches
+	s.batches = nil
+	if current {
+		batches = append(batches, s.events)
+		s.events = nil
+	}
+	s.mu.Unlock()
+	var errs []error
+	for _, events := range batches {
+		if err := s.sendBatch(events); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendBatch sends the events to the HTTP Event Collector. If Ack is true, it waits for the acknowledgement of the
+// indexer. It is called with sendMu locked.
+func (s *SplunkSink) sendBatch(events [][]byte) error {
+	if len(events) == 0 {
+		return nil
+	}
+	metrics.queue.add(-float64(len(events)), sinkSplunk)
+	ackID, err := s.send(bytes.Join(events, []byte("\n")))
+	if err != nil {
+		return err
+	}
+	if s.config.Ack && ackID != nil {
+		return s.waitAck(*ackID)
+	}
+	return nil
+}
+
+// Close stops the periodic flush and sends the pending events.
+func (s *SplunkSink) Close() error {
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+		<-s.done
+	}
+	return s.Flush()
+}
+
+// flushLoop sends the full batches when they are queued, and the batch every FlushInterval, until the sink is closed.
+func (s *SplunkSink) flushLoop() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.queued:
+			if err := s.sendBatches(false); err != nil {
+				fmt.Println(err)
+			}
+		case <-ticker.C:
+			if err := s.Flush(); err != nil {
+				fmt.Println(err)
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// send posts the batch to the HTTP Event Collector. It retries when the server is busy.
+// It returns the acknowledgement ID of the batch when the channel is used.
+func (s *SplunkSink) send(body []byte) (*int64, error) {
+	var err error
+	for attempt := 0; attempt <= s.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+			metrics.retries.add(1, sinkSplunk)
+		}
+		var response splunkResponse
+		start := time.Now()
+		response, err = s.post(s.config.URL+splunkEventPath, body)
+		metrics.delivered(sinkSplunk, len(body), start, err)
+		if err == nil {
+			return response.AckID, nil
+		}
+		var splunkErr *SplunkError
+		if !errors.As(err, &splunkErr) || !splunkErr.retryable() {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// waitAck polls the acknowledgement endpoint until the batch is indexed or the AckTimeout is reached.
+func (s *SplunkSink) waitAck(ackID int64) error {
+	body, err := json.Marshal(map[string][]int64{"acks": {ackID}})
+	if err != nil {
+		return fmt.Errorf("splunk: error marshaling ack: %w", err)
+	}
+	deadline := time.Now().Add(s.config.AckTimeout)
+	for {
+		var response struct {
+			Acks map[string]bool `json:"acks"`
+		}
+		err := s.postJSON(s.config.URL+splunkAckPath+"?channel="+s.config.Channel, body, &response)
+		if err != nil {
+			return err
+		}
+		if response.Acks[fmt.Sprint(ackID)] {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("splunk: timeout waiting for the acknowledgement %d", ackID)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// post sends the body to the URL and decodes the response of the HTTP Event Collector.
+func (s *SplunkSink) post(url string, body []byte) (splunkResponse, error) {
+	var response splunkResponse
+	err := s.postJSON(url, body, &response)
+	return response, err
+}
+
+// postJSON sends the body to the URL with the authorization of the token and decodes the response in value.
+func (s *SplunkSink) postJSON(url string, body []byte, value interface{}) error {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("splunk: %w", err)
+	}
+	request.Header.Set("Authorization", "Splunk "+s.config.Token)
+	request.Header.Set("Content-Type", "application/json")
+	if s.config.Channel != "" {
+		request.Header.Set("X-Splunk-Request-Channel", s.config.Channel)
+	}
+	response, err := s.config.Client.Do(request)
+	if err != nil {
+		return fmt.Errorf("splunk: %w", err)
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("splunk: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		splunkErr := &SplunkError{StatusCode: response.StatusCode, Text: http.StatusText(response.StatusCode)}
+		var errResponse splunkResponse
+		if json.Unmarshal(data, &errResponse) == nil && errResponse.Text != "" {
+			splunkErr.Code = errResponse.Code
+			splunkErr.Text = errResponse.Text
+		}
+		return splunkErr
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("splunk: error decoding response: %w", err)
+	}
+	return nil
+}
+
+// newChannel returns a random UUID used as the channel of the acknowledgement.
+func newChannel() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
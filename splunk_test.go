@@ -0,0 +1,228 @@
+package logs
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// splunkServer is a fake HTTP Event Collector used in the tests.
+type splunkServer struct {
+	mu      sync.Mutex
+	events  []splunkEvent
+	headers []http.Header
+	acked   []string
+}
+
+// count returns the number of events received.
+func (f *splunkServer) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.events)
+}
+
+func (f *splunkServer) handler(status int, response string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.headers = append(f.headers, r.Header.Clone())
+		if r.URL.Path == splunkAckPath {
+			body, _ := io.ReadAll(r.Body)
+			f.acked = append(f.acked, string(body))
+			_, _ = w.Write([]byte(`{"acks":{"7":true}}`))
+			return
+		}
+		decoder := json.NewDecoder(r.Body)
+		for decoder.More() {
+			var event splunkEvent
+			if err := decoder.Decode(&event); err != nil {
+				break
+			}
+			f.events = append(f.events, event)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}
+}
+
+func TestSplunkSink_Write(t *testing.T) {
+	type args struct {
+		config  SplunkConfig
+		entries int
+	}
+	type want struct {
+		events int
+		source string
+		index  string
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "SplunkSink sends the batch when it is full",
+			args: args{
+				config:  SplunkConfig{Token: "token", BatchSize: 2, Index: "main"},
+				entries: 2,
+			},
+			want: want{events: 2, source: "Test", index: "main"},
+		},
+		{
+			name: "SplunkSink keeps the events until the batch is full",
+			args: args{
+				config:  SplunkConfig{Token: "token", BatchSize: 3, Source: "custom"},
+				entries: 2,
+			},
+			want: want{events: 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &splunkServer{}
+			server := httptest.NewServer(fake.handler(http.StatusOK, `{"text":"Success","code":0}`))
+			defer server.Close()
+			tt.args.config.URL = server.URL
+			tt.args.config.FlushInterval = time.Hour
+			sink := NewSplunkSink(tt.args.config)
+			for i := 0; i < tt.args.entries; i++ {
+				err := sink.Write(Entry{Time: time.Now(), Level: LevelError, NameApp: "Test", Message: "message"})
+				assert.NoError(t, err)
+			}
+
+			if tt.want.events > 0 {
+				assert.Eventually(t, func() bool { return fake.count() == tt.want.events }, time.Second, 5*time.Millisecond)
+				assert.Equal(t, tt.want.source, fake.events[0].Source)
+				assert.Equal(t, tt.want.index, fake.events[0].Index)
+				assert.Equal(t, "_json", fake.events[0].SourceType)
+				assert.Equal(t, LevelError, fake.events[0].Event.Level)
+				assert.Equal(t, "Splunk token", fake.headers[0].Get("Authorization"))
+			}
+			assert.Equal(t, tt.want.events, fake.count())
+		})
+	}
+}
+
+func TestSplunkSink_Write_queue(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`{"text":"Success","code":0}`))
+	}))
+	defer server.Close()
+	sink := NewSplunkSink(SplunkConfig{URL: server.URL, Token: "token", BatchSize: 1, QueueSize: 1, FlushInterval: time.Hour})
+	entry := Entry{Time: time.Now(), Level: LevelInfo, NameApp: "Test", Message: "message"}
+
+	start := time.Now()
+	assert.NoError(t, sink.Write(entry))
+	assert.Eventually(t, func() bool {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		return len(sink.batches) == 0
+	}, time.Second, 5*time.Millisecond)
+	assert.NoError(t, sink.Write(entry))
+	assert.EqualError(t, sink.Write(entry), "splunk: the queue is full, 1 events were dropped")
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	close(release)
+	assert.NoError(t, sink.Close())
+}
+
+func TestService_Fatal_splunk(t *testing.T) {
+	fake := &splunkServer{}
+	handler := fake.handler(http.StatusOK, `{"text":"Success","code":0}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		handler(w, r)
+	}))
+	defer server.Close()
+	sink := NewSplunkSink(SplunkConfig{URL: server.URL, Token: "token", BatchSize: 1, FlushInterval: time.Hour})
+	defer sink.Close()
+	delivered := -1
+	logService := NewService(Service{NameApp: "Test", Sinks: []Sink{sink}, ExitFunc: func(int) { delivered = fake.count() }})
+
+	logService.Info("first")
+	logService.Fatal("second")
+
+	assert.Equal(t, 2, delivered)
+}
+
+func TestSplunkSink_Flush(t *testing.T) {
+	type want struct {
+		code  int
+		calls int
+	}
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		want     want
+	}{
+		{
+			name:     "Flush when the token is invalid",
+			status:   http.StatusForbidden,
+			response: `{"text":"Invalid token","code":4}`,
+			want:     want{code: 4, calls: 1},
+		},
+		{
+			name:     "Flush retries when the server is busy",
+			status:   http.StatusServiceUnavailable,
+			response: `{"text":"Server is busy","code":9}`,
+			want:     want{code: 9, calls: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &splunkServer{}
+			server := httptest.NewServer(fake.handler(tt.status, tt.response))
+			defer server.Close()
+			sink := NewSplunkSink(SplunkConfig{URL: server.URL, Token: "token", MaxRetries: 1, FlushInterval: time.Hour})
+			_ = sink.Write(Entry{Time: time.Now(), Level: LevelInfo, Message: "message"})
+			err := sink.Flush()
+
+			var splunkErr *SplunkError
+			assert.True(t, errors.As(err, &splunkErr))
+			assert.Equal(t, tt.want.code, splunkErr.Code)
+			assert.Len(t, fake.headers, tt.want.calls)
+		})
+	}
+}
+
+func TestSplunkSink_Ack(t *testing.T) {
+	fake := &splunkServer{}
+	server := httptest.NewServer(fake.handler(http.StatusOK, `{"text":"Success","code":0,"ackId":7}`))
+	defer server.Close()
+	sink := NewSplunkSink(SplunkConfig{URL: server.URL, Token: "token", Ack: true, FlushInterval: time.Hour})
+	_ = sink.Write(Entry{Time: time.Now(), Level: LevelInfo, Message: "message"})
+	err := sink.Close()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"acks":[7]}`}, fake.acked)
+	channel := fake.headers[0].Get("X-Splunk-Request-Channel")
+	assert.Len(t, strings.Split(channel, "-"), 5)
+	assert.Equal(t, channel, fake.headers[1].Get("X-Splunk-Request-Channel"))
+}
+
+func TestService_Sinks(t *testing.T) {
+	fake := &splunkServer{}
+	server := httptest.NewServer(fake.handler(http.StatusOK, `{"text":"Success","code":0}`))
+	defer server.Close()
+	logService := NewService(Service{
+		NameApp: "Test",
+		Sinks:   []Sink{NewSplunkSink(SplunkConfig{URL: server.URL, Token: "token", FlushInterval: time.Hour})},
+	})
+	logService.Error("message", "extra")
+	logService.Flush()
+
+	assert.Len(t, fake.events, 1)
+	assert.Equal(t, "message extra", fake.events[0].Event.Message)
+	assert.Equal(t, "splunk_test.go", fake.events[0].Event.File)
+}
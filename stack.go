@@ -0,0 +1,86 @@
+package logs
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// discordMaxContent is the maximum number of characters of a Discord message.
+	discordMaxContent = 2000
+	// stackMaxFrames is the maximum number of frames captured in a stack trace.
+	stackMaxFrames = 64
+)
+
+// Frame is a frame of the stack trace of a log.
+type Frame struct {
+	// Function is the name of the function with its package.
+	Function string `json:"function"`
+	// File is the full path of the file.
+	File string `json:"file"`
+	// Line is the line of the file.
+	Line int `json:"line"`
+}
+
+// stackDecorator sets in the entry the stack trace of the goroutine when the level of the entry is equal or above
+// the StackLevel of the service.
+func (s Service) stackDecorator(entry *Entry) {
+	if s.StackLevel == "" || entry.Level.severity() < s.StackLevel.severity() {
+		return
+	}
+	entry.Stack = stack()
+}
+
+// stack returns the stack trace of the goroutine. The frames of the runtime and of this package are dropped.
+func stack() []Frame {
+	pcs := make([]uintptr, stackMaxFrames)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	var stack []Frame
+	for {
+		frame, more := frames.Next()
+		if !internalFrame(frame) && !strings.HasPrefix(frame.Function, "runtime.") {
+			stack = append(stack, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+		if !more {
+			return stack
+		}
+	}
+}
+
+// stackText returns the stack trace as a multi-line block, in the same layout of the stack traces of Go.
+func stackText(stack []Frame) string {
+	var builder strings.Builder
+	for i, frame := range stack {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		fmt.Fprintf(&builder, "%s()\n\t%s:%d", frame.Function, frame.File, frame.Line)
+	}
+	return builder.String()
+}
+
+// discordContent returns the content of the entry for a Discord message. The stack trace is sent as a code block
+// and it is truncated to fit in the maximum size of a message.
+func discordContent(entry Entry) string {
+	content := entry.Content
+	if len(entry.Stack) == 0 || !strings.HasSuffix(content, "\n"+stackText(entry.Stack)) {
+		return truncate(content, discordMaxContent)
+	}
+	content = truncate(strings.TrimSuffix(content, "\n"+stackText(entry.Stack)), discordMaxContent/2)
+	block := truncate(stackText(entry.Stack), discordMaxContent-len(content)-len("\n```\n\n```"))
+	return content + "\n```\n" + block + "\n```"
+}
+
+// truncate returns the text cut to the maximum number of bytes provided, ending with "..." when it is cut.
+func truncate(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	end := max - 3
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end] + "..."
+}
@@ -0,0 +1,89 @@
+package logs
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_stackDecorator(t *testing.T) {
+	type want struct {
+		stack bool
+	}
+	tests := []struct {
+		name       string
+		stackLevel Level
+		level      Level
+		want       want
+	}{
+		{
+			name:       "stackDecorator when the level is the StackLevel",
+			stackLevel: LevelError,
+			level:      LevelError,
+			want:       want{stack: true},
+		},
+		{
+			name:       "stackDecorator when the level is above the StackLevel",
+			stackLevel: LevelError,
+			level:      LevelFatal,
+			want:       want{stack: true},
+		},
+		{
+			name:       "stackDecorator when the level is below the StackLevel",
+			stackLevel: LevelError,
+			level:      LevelWarning,
+			want:       want{stack: false},
+		},
+		{
+			name:  "stackDecorator when the StackLevel is not provided",
+			level: LevelFatal,
+			want:  want{stack: false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logService := NewService(Service{StackLevel: tt.stackLevel})
+			entry := logService.logBuilder(tt.level, "message")
+
+			assert.Equal(t, tt.want.stack, len(entry.Stack) > 0)
+			if tt.want.stack {
+				assert.Equal(t, "github.com/fsandov/logs.TestService_stackDecorator.func1", entry.Stack[0].Function)
+				for _, frame := range entry.Stack {
+					assert.False(t, strings.HasPrefix(frame.Function, "runtime."))
+				}
+			}
+		})
+	}
+}
+
+func TestService_logContentStack(t *testing.T) {
+	stack := []Frame{
+		{Function: "main.run", File: "/src/main.go", Line: 12},
+		{Function: "main.main", File: "/src/main.go", Line: 5},
+	}
+	entry := Entry{Level: LevelError, NameApp: "LOGS", Message: "message", File: "main.go", Line: 12, Function: "run", Stack: stack}
+
+	text := NewService(Service{}).logContent(entry, "message")
+	assert.Equal(t, "[LOGS]-[ERROR] main.go:12:run(): message\nmain.run()\n\t/src/main.go:12\nmain.main()\n\t/src/main.go:5", text)
+
+	var value jsonEntry
+	assert.NoError(t, json.Unmarshal([]byte(NewService(Service{Format: FormatJSON}).logContent(entry, "message")), &value))
+	assert.Equal(t, stack, value.Stack)
+	assert.Equal(t, "message", value.Message)
+}
+
+func Test_discordContent(t *testing.T) {
+	stack := []Frame{{Function: "main.run", File: "/src/main.go", Line: 12}}
+	entry := Entry{Content: "[LOGS]-[ERROR] main.go:12:run(): message\n" + stackText(stack), Stack: stack}
+	assert.Equal(t, "[LOGS]-[ERROR] main.go:12:run(): message\n```\nmain.run()\n\t/src/main.go:12\n```", discordContent(entry))
+
+	for i := 0; i < 200; i++ {
+		entry.Stack = append(entry.Stack, stack[0])
+	}
+	entry.Content = "[LOGS]-[ERROR] main.go:12:run(): message\n" + stackText(entry.Stack)
+	content := discordContent(entry)
+	assert.LessOrEqual(t, len(content), discordMaxContent)
+	assert.True(t, strings.HasSuffix(content, "...\n```"))
+}
@@ -0,0 +1,136 @@
+package logs
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LevelWriter is an io.Writer that registers every line written as a log of the service. It is used to connect
+// the libraries that write to a *log.Logger or an io.Writer with the outputs and sinks of the service.
+type LevelWriter struct {
+	// parseLevel is true when the level at the start of the line is used instead of the level of the writer.
+	parseLevel bool
+	service    Service
+	level      Level
+	mu         sync.Mutex
+	buffer     []byte
+}
+
+// WriterOption is an option of the LevelWriter returned by Writer and StdLogger.
+type WriterOption func(w *LevelWriter)
+
+// ParseLevelPrefix is the option that uses the level at the start of the line instead of the level of the writer.
+// The level can be written as "[ERROR]" or "ERROR:", in any case. It is removed from the message.
+// A line that only starts with the word of a level, like "Error connecting to db", keeps the level of the writer.
+// Example: service.StdLogger(logs.LevelInfo, logs.ParseLevelPrefix())
+func ParseLevelPrefix() WriterOption {
+	return func(w *LevelWriter) {
+		w.parseLevel = true
+	}
+}
+
+// Writer returns a LevelWriter that registers the lines written with the level and the options provided.
+func (s Service) Writer(level Level, options ...WriterOption) *LevelWriter {
+	w := &LevelWriter{service: s, level: level}
+	for _, option := range options {
+		option(w)
+	}
+	return w
+}
+
+// StdLogger returns a *log.Logger that registers the lines written with the level and the options provided.
+// The date, time and caller are added by the service, so the logger has no flags.
+func (s Service) StdLogger(level Level, options ...WriterOption) *log.Logger {
+	return log.New(s.Writer(level, options...), "", 0)
+}
+
+// Write registers every complete line of p as a log. The incomplete last line is kept until the next write or Flush.
+func (w *LevelWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buffer = append(w.buffer, p...)
+	for {
+		i := bytes.IndexByte(w.buffer, '\n')
+		if i < 0 {
+			break
+		}
+		w.register(string(w.buffer[:i]))
+		w.buffer = w.buffer[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush registers the incomplete last line written, if there is one.
+func (w *LevelWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buffer) > 0 {
+		w.register(string(w.buffer))
+		w.buffer = nil
+	}
+	return nil
+}
+
+// register builds the entry of the line and registers it in the service. The empty lines are ignored.
+func (w *LevelWriter) register(line string) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	level := w.level
+	if w.parseLevel {
+		if parsed, rest, ok := parseLevelPrefix(line); ok {
+			level, line = parsed, rest
+		}
+	}
+	if !w.service.Enabled(level) {
+		return
+	}
+	entry := w.service.logDecorator(Entry{
+		Time:    time.Now(),
+		Level:   level,
+		NameApp: w.service.NameApp,
+		Message: line,
+		Fields:  w.service.fields,
+	})
+	entry.Content = w.service.logContent(entry, line)
+	w.service.registerOrchestrator(entry)
+}
+
+// parseLevelPrefix returns the level at the start of the line and the rest of the line. The level must be written
+// between brackets, like "[ERROR]", or followed by a colon, like "ERROR:".
+func parseLevelPrefix(line string) (Level, string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	var word, rest string
+	var found bool
+	if strings.HasPrefix(trimmed, "[") {
+		word, rest, found = strings.Cut(trimmed[1:], "]")
+	} else {
+		word, rest, found = strings.Cut(trimmed, ":")
+	}
+	if !found {
+		return "", line, false
+	}
+	level, ok := levelNames[strings.ToUpper(word)]
+	if !ok {
+		return "", line, false
+	}
+	return level, strings.TrimLeft(rest, " "), true
+}
+
+// levelNames are the names accepted for every level when they are parsed.
+var levelNames = map[string]Level{
+	"TRACE":   LevelTrace,
+	"DEBUG":   LevelDebug,
+	"INFO":    LevelInfo,
+	"NOTICE":  LevelNotice,
+	"WARN":    LevelWarning,
+	"WARNING": LevelWarning,
+	"ERROR":   LevelError,
+	"ERR":     LevelError,
+	"PANIC":   LevelPanic,
+	"FATAL":   LevelFatal,
+}
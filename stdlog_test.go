@@ -0,0 +1,117 @@
+package logs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_StdLogger(t *testing.T) {
+	sink := &recordSink{}
+	logService := NewService(Service{NameApp: "Test", Sinks: []Sink{sink}})
+	logService.StdLogger(LevelWarning).Printf("connection %d lost", 3)
+
+	assert.Len(t, sink.entries, 1)
+	entry := sink.entries[0]
+	assert.Equal(t, LevelWarning, entry.Level)
+	assert.Equal(t, "connection 3 lost", entry.Message)
+	assert.Equal(t, "stdlog_test.go", entry.File)
+	assert.Equal(t, "TestService_StdLogger", entry.Function)
+}
+
+func TestService_StdLogger_parseLevel(t *testing.T) {
+	sink := &recordSink{}
+	logService := NewService(Service{NameApp: "Test", Sinks: []Sink{sink}})
+	logger := logService.StdLogger(LevelInfo, ParseLevelPrefix())
+
+	logger.Print("[ERROR] connection lost")
+	logger.Print("Error connecting to db")
+
+	assert.Len(t, sink.entries, 2)
+	assert.Equal(t, LevelError, sink.entries[0].Level)
+	assert.Equal(t, "connection lost", sink.entries[0].Message)
+	assert.Equal(t, LevelInfo, sink.entries[1].Level)
+	assert.Equal(t, "Error connecting to db", sink.entries[1].Message)
+}
+
+func TestLevelWriter_Write(t *testing.T) {
+	type want struct {
+		levels   []Level
+		messages []string
+	}
+	tests := []struct {
+		name       string
+		parseLevel bool
+		writes     []string
+		want       want
+	}{
+		{
+			name:   "Write registers every line",
+			writes: []string{"first\nsecond\n"},
+			want:   want{levels: []Level{LevelInfo, LevelInfo}, messages: []string{"first", "second"}},
+		},
+		{
+			name:   "Write keeps the incomplete line until it is completed",
+			writes: []string{"fir", "st\r\n", "\n", "second"},
+			want:   want{levels: []Level{LevelInfo}, messages: []string{"first"}},
+		},
+		{
+			name:       "Write parses the level of the lines",
+			parseLevel: true,
+			writes:     []string{"[ERROR] first\nwarn: second\n[debug]third\nunknown: fourth\n"},
+			want: want{
+				levels:   []Level{LevelError, LevelWarning, LevelDebug, LevelInfo},
+				messages: []string{"first", "second", "third", "unknown: fourth"},
+			},
+		},
+		{
+			name:       "Write keeps the lines that start with the word of a level",
+			parseLevel: true,
+			writes:     []string{"Error connecting to db\ninfo about the user: 7\n[ERROR first\n"},
+			want: want{
+				levels:   []Level{LevelInfo, LevelInfo, LevelInfo},
+				messages: []string{"Error connecting to db", "info about the user: 7", "[ERROR first"},
+			},
+		},
+		{
+			name:   "Write does not parse the level when it is disabled",
+			writes: []string{"[ERROR] first\n"},
+			want:   want{levels: []Level{LevelInfo}, messages: []string{"[ERROR] first"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordSink{}
+			var options []WriterOption
+			if tt.parseLevel {
+				options = append(options, ParseLevelPrefix())
+			}
+			writer := NewService(Service{Sinks: []Sink{sink}}).Writer(LevelInfo, options...)
+			for _, write := range tt.writes {
+				_, err := fmt.Fprint(writer, write)
+				assert.NoError(t, err)
+			}
+
+			var levels []Level
+			var messages []string
+			for _, entry := range sink.entries {
+				levels = append(levels, entry.Level)
+				messages = append(messages, entry.Message)
+			}
+			assert.Equal(t, tt.want.levels, levels)
+			assert.Equal(t, tt.want.messages, messages)
+		})
+	}
+}
+
+func TestLevelWriter_Flush(t *testing.T) {
+	sink := &recordSink{}
+	writer := NewService(Service{Sinks: []Sink{sink}}).Writer(LevelNotice)
+	_, _ = writer.Write([]byte("incomplete"))
+	assert.Len(t, sink.entries, 0)
+
+	assert.NoError(t, writer.Flush())
+	assert.Len(t, sink.entries, 1)
+	assert.Equal(t, "incomplete", sink.entries[0].Message)
+}
@@ -0,0 +1,140 @@
+package logs
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syslogFacilities are the syslog facilities by name.
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// SyslogConfig is the struct that contains the configuration of the SyslogSink. Only the Address is required.
+type SyslogConfig struct {
+	// Address is the host and port of the syslog server. Example: syslog.example.com:514
+	Address string
+	// Network is the protocol used to send the messages, "udp" or "tcp". If it is not provided, it will be "udp".
+	// The TCP messages are framed with their length, as in RFC 6587.
+	Network string
+	// Facility is the name of the syslog facility of the messages. Example: local0
+	// If it is not provided, it will be "user".
+	Facility string
+	// Tag is the name of the application of the messages. If it is not provided, it will be the name of the application of the entry.
+	Tag string
+	// Host is the host of the messages. If it is not provided, it will be the hostname of the machine.
+	Host string
+}
+
+// SyslogSink is a sink that sends the logs to a syslog server in the RFC 5424 format, over UDP or TCP.
+// The fields are appended to the message as key=value pairs.
+type SyslogSink struct {
+	config   SyslogConfig
+	facility int
+	mu       sync.Mutex
+	conn     net.Conn
+}
+
+// NewSyslogSink returns a new SyslogSink with the configuration provided. The connection is opened with the first log.
+func NewSyslogSink(config SyslogConfig) *SyslogSink {
+	if config.Network == "" {
+		config.Network = "udp"
+	}
+	if config.Facility == "" {
+		config.Facility = "user"
+	}
+	if config.Host == "" {
+		config.Host, _ = os.Hostname()
+	}
+	facility, ok := syslogFacilities[strings.ToLower(config.Facility)]
+	if !ok {
+		facility = syslogFacilities["user"]
+	}
+	return &SyslogSink{config: config, facility: facility}
+}
+
+// Write sends the entry to the syslog server.
+func (s *SyslogSink) Write(entry Entry) error {
+	message := s.message(entry)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.config.Network == "tcp" {
+		message = fmt.Sprintf("%d %s", len(message), message)
+	}
+	start := time.Now()
+	err := s.write(message)
+	metrics.delivered(sinkSyslog, len(message), start, err)
+	return err
+}
+
+// write sends the message to the syslog server. The TCP connection is opened again once when the message fails.
+// It must be called with the lock.
+func (s *SyslogSink) write(message string) error {
+	for attempt := 0; ; attempt++ {
+		if err := s.connect(); err != nil {
+			return err
+		}
+		_, err := s.conn.Write([]byte(message))
+		if err == nil || attempt > 0 || s.config.Network != "tcp" {
+			return err
+		}
+		_ = s.conn.Close()
+		s.conn = nil
+		metrics.retries.add(1, sinkSyslog)
+	}
+}
+
+// Close closes the connection with the syslog server.
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// message returns the RFC 5424 message of the entry. Example: <11>1 2024-01-02T15:04:05Z host app 42 - - message
+func (s *SyslogSink) message(entry Entry) string {
+	tag := s.config.Tag
+	if tag == "" {
+		tag = entry.NameApp
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s %d - - %s",
+		s.facility*8+entry.Level.syslogSeverity(),
+		entry.Time.UTC().Format(time.RFC3339Nano),
+		syslogHeader(s.config.Host),
+		syslogHeader(tag),
+		os.Getpid(),
+		fieldsMessage(entry.Message, flatFields(entry.Fields)))
+}
+
+// connect opens the connection with the syslog server if it is not open.
+func (s *SyslogSink) connect() error {
+	if s.conn != nil {
+		return nil
+	}
+	conn, err := net.Dial(s.config.Network, s.config.Address)
+	if err != nil {
+		return fmt.Errorf("syslog: %w", err)
+	}
+	s.conn = conn
+	return nil
+}
+
+// syslogHeader returns the value of a field of the header, without spaces. The empty values are "-".
+func syslogHeader(value string) string {
+	value = strings.Join(strings.Fields(value), "_")
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,58 @@
+package logs
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyslogSink_Write(t *testing.T) {
+	entry := Entry{
+		Time:    time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		Level:   LevelError,
+		NameApp: "LOGS",
+		Message: "message",
+		Fields:  []Field{Any("user", "fsandov")},
+	}
+	want := fmt.Sprintf("<131>1 2024-01-02T15:04:05Z host api %d - - message user=fsandov", os.Getpid())
+
+	t.Run("Write with UDP", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+		sink := NewSyslogSink(SyslogConfig{Address: conn.LocalAddr().String(), Facility: "local0", Tag: "api", Host: "host"})
+		defer sink.Close()
+
+		require.NoError(t, sink.Write(entry))
+		buffer := make([]byte, 1024)
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buffer)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(buffer[:n]))
+	})
+
+	t.Run("Write with TCP", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		sink := NewSyslogSink(SyslogConfig{Address: listener.Addr().String(), Network: "tcp", Facility: "local0", Tag: "api", Host: "host"})
+		defer sink.Close()
+
+		require.NoError(t, sink.Write(entry))
+		conn, err := listener.Accept()
+		require.NoError(t, err)
+		defer conn.Close()
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		framed := fmt.Sprintf("%d %s", len(want), want)
+		buffer := make([]byte, len(framed))
+		_, err = io.ReadFull(conn, buffer)
+		require.NoError(t, err)
+		assert.Equal(t, framed, string(buffer))
+	})
+}
@@ -0,0 +1,59 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookConfig is the struct that contains the configuration of the WebhookSink. Only the URL is required.
+type WebhookConfig struct {
+	// URL is the URL of the webhook. Example: https://discordapp.com/api/webhooks/1234567890/abcdefghijklmnopqrstuvwxyz
+	URL string
+	// Formatter is the format of the logs. If it is not provided, the logs are sent in the format of the service.
+	Formatter Formatter
+	// Client is the HTTP client used to send the logs. If it is not provided, http.DefaultClient is used.
+	Client *http.Client
+}
+
+// WebhookSink is a sink that sends every log as a Discord message, like the URL of the service.
+// The stack trace is sent as a code block.
+type WebhookSink struct {
+	config WebhookConfig
+}
+
+// NewWebhookSink returns a new WebhookSink with the configuration provided.
+func NewWebhookSink(config WebhookConfig) *WebhookSink {
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	return &WebhookSink{config: config}
+}
+
+// Write sends the entry to the webhook.
+func (w *WebhookSink) Write(entry Entry) error {
+	entry.Content = w.config.Formatter.content(entry)
+	body, err := json.Marshal(map[string]string{"content": discordContent(entry)})
+	if err != nil {
+		return fmt.Errorf("webhook: error marshaling message: %w", err)
+	}
+	start := time.Now()
+	err = w.post(body)
+	metrics.delivered(sinkWebhook, len(body), start, err)
+	return err
+}
+
+// post sends the body to the webhook.
+func (w *WebhookSink) post(body []byte) error {
+	response, err := w.config.Client.Post(w.config.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook: unexpected status %d", response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package logs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSink_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr string
+	}{
+		{
+			name:   "Write with a successful response",
+			status: http.StatusNoContent,
+		},
+		{
+			name:    "Write with an error response",
+			status:  http.StatusTooManyRequests,
+			wantErr: "webhook: unexpected status 429",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+			sink := NewWebhookSink(WebhookConfig{URL: server.URL})
+
+			err := sink.Write(Entry{Content: "[LOGS]-[ERROR] message"})
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, map[string]string{"content": "[LOGS]-[ERROR] message"}, body)
+		})
+	}
+}